@@ -0,0 +1,452 @@
+package tuitestkit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StorageMode selects where golden snapshots are kept.
+type StorageMode int
+
+const (
+	// StorageFromEnv follows SnapshotStorage (the default for a
+	// Snapshotter); as the value of SnapshotStorage it means StorageFiles.
+	StorageFromEnv StorageMode = iota
+	// StorageFiles keeps one <name>.golden file per snapshot (the default).
+	StorageFiles
+	// StorageArchive keeps all snapshots of a package in one txtar archive,
+	// testdata/snapshots/snapshots.txtar, with one entry per snapshot name.
+	StorageArchive
+	// StorageArchivePerFile keeps one txtar archive per test file, e.g.
+	// testdata/snapshots/board_test.txtar for board_test.go.
+	StorageArchivePerFile
+)
+
+// SnapshotStorage is the storage backend used by the Snapshot* functions and
+// by Snapshotters that leave Storage unset. Set via
+// SNAPSHOT_STORAGE=files|archive|archive-per-file or directly in TestMain.
+// Switching an existing package over is a one-off migration with
+// MigrateToArchive / MigrateFromArchive.
+var SnapshotStorage StorageMode
+
+// packageArchive is the archive name used by StorageArchive.
+const packageArchive = "snapshots.txtar"
+
+func init() {
+	switch v := os.Getenv("SNAPSHOT_STORAGE"); v {
+	case "", "files":
+	case "archive":
+		SnapshotStorage = StorageArchive
+	case "archive-per-file":
+		SnapshotStorage = StorageArchivePerFile
+	default:
+		fmt.Fprintf(os.Stderr, "tuitestkit: ignoring SNAPSHOT_STORAGE=%q (want files, archive or archive-per-file)\n", v)
+	}
+}
+
+// goldenRef identifies a golden snapshot: a .golden file, or an entry in a
+// txtar archive.
+type goldenRef struct {
+	path string // the .golden file, or the archive holding the entry
+	key  string // entry name in the archive; "" for a .golden file
+}
+
+// archiveRefSep joins an archive path and an entry name in messages and in
+// the orphan bookkeeping, e.g. "testdata/snapshots/snapshots.txtar#board".
+const archiveRefSep = ".txtar#"
+
+// String names the snapshot for messages.
+func (g goldenRef) String() string {
+	if g.key == "" {
+		return g.path
+	}
+	return strings.TrimSuffix(g.path, ".txtar") + archiveRefSep + g.key
+}
+
+// pendingString names where a pending snapshot for g is saved.
+func (g goldenRef) pendingString() string {
+	if g.key == "" {
+		return g.path + pendingSuffix
+	}
+	return g.path + pendingSuffix + "#" + g.key
+}
+
+// parseGoldenRef is the inverse of goldenRef.String.
+func parseGoldenRef(s string) goldenRef {
+	if i := strings.LastIndex(s, archiveRefSep); i >= 0 {
+		return goldenRef{path: s[:i] + ".txtar", key: s[i+len(archiveRefSep):]}
+	}
+	return goldenRef{path: s}
+}
+
+// ref resolves where the snapshot called name is stored under the
+// Snapshotter's storage mode. callerSkip locates the test file as in
+// snapshotDir.
+func (s *Snapshotter) ref(name string, callerSkip int) goldenRef {
+	dir := s.dir(callerSkip + 1)
+	storage := s.Storage
+	if storage == StorageFromEnv {
+		storage = SnapshotStorage
+	}
+	switch storage {
+	case StorageArchive:
+		return goldenRef{path: filepath.Join(dir, packageArchive), key: name}
+	case StorageArchivePerFile:
+		_, file, _, ok := runtime.Caller(callerSkip)
+		if !ok {
+			panic("tuitestkit: cannot determine caller file for snapshot archive")
+		}
+		base := strings.TrimSuffix(filepath.Base(file), ".go")
+		return goldenRef{path: filepath.Join(dir, base+".txtar"), key: name}
+	}
+	return goldenRef{path: filepath.Join(dir, name+".golden")}
+}
+
+// read returns the golden content; a missing file or entry is fs.ErrNotExist.
+func (g goldenRef) read() (string, error) {
+	if g.key == "" {
+		data, err := os.ReadFile(g.path)
+		return string(data), err
+	}
+	return readArchiveEntry(g.path, g.key)
+}
+
+// write stores content, creating parent directories as needed. Files are
+// replaced atomically, so parallel tests never read a half-written golden file.
+func (g goldenRef) write(content string) error {
+	if g.key == "" {
+		defer lockGolden(g.path)()
+		return writeFileAtomic(g.path, content)
+	}
+	return setArchiveEntry(g.path, g.key, content)
+}
+
+// --- txtar archives ---
+
+// Archives use the txtar format: a comment, then one entry per snapshot,
+// sorted by name so rewrites produce stable diffs:
+//
+//	-- board-list --
+//	<content>
+//	-- board-empty --
+//	<content>
+//
+// Every entry gets a final newline that is not part of the snapshot, so
+// content without a trailing newline survives the round trip. Content lines
+// that look like an entry marker are escaped with a leading backslash.
+const archiveComment = "Golden snapshots written by tuitestkit, one entry per snapshot.\nRegenerate with UPDATE_SNAPSHOTS=1.\n"
+
+var archiveMarkerRe = regexp.MustCompile(`^-- (.+) --$`)
+
+// archiveEntries maps entry names to content.
+type archiveEntries map[string]string
+
+// parseArchive reads a txtar archive. The comment is ignored.
+func parseArchive(data string) archiveEntries {
+	entries := archiveEntries{}
+	var name string
+	var body []string
+	inEntry := false
+	flush := func() {
+		if inEntry {
+			entries[name] = strings.Join(body, "\n")
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+	for _, line := range lines {
+		if m := archiveMarkerRe.FindStringSubmatch(line); m != nil {
+			flush()
+			name, body, inEntry = strings.TrimSpace(m[1]), nil, true
+			continue
+		}
+		if strings.HasPrefix(line, `\`) && archiveMarkerRe.MatchString(strings.TrimLeft(line, `\`)) {
+			line = line[1:]
+		}
+		body = append(body, line)
+	}
+	flush()
+	return entries
+}
+
+// format renders the archive with entries sorted by name.
+func (a archiveEntries) format() string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(archiveComment)
+	for _, name := range names {
+		b.WriteString("-- " + name + " --\n")
+		for _, line := range strings.Split(a[name], "\n") {
+			if archiveMarkerRe.MatchString(strings.TrimLeft(line, `\`)) {
+				line = `\` + line
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// goldenLocks serializes writes per golden file, and read-modify-write cycles
+// per archive, so parallel tests updating the same archive do not lose each
+// other's entries.
+var goldenLocks = struct {
+	sync.Mutex
+	byPath map[string]*sync.Mutex
+}{byPath: map[string]*sync.Mutex{}}
+
+// lockGolden locks the golden file or archive at path and returns the unlock
+// function.
+func lockGolden(path string) func() {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	goldenLocks.Lock()
+	mu, ok := goldenLocks.byPath[path]
+	if !ok {
+		mu = &sync.Mutex{}
+		goldenLocks.byPath[path] = mu
+	}
+	goldenLocks.Unlock()
+	mu.Lock()
+	return mu.Unlock
+}
+
+// archiveCache keeps parsed archives by absolute path, so a package with many
+// snapshots in one archive parses it once rather than on every read. An entry
+// is used only while the file's size and modification time are unchanged, so
+// edits from outside the process (snapreview, git checkout) are picked up.
+var archiveCache = struct {
+	sync.Mutex
+	byPath map[string]cachedArchive
+}{byPath: map[string]cachedArchive{}}
+
+type cachedArchive struct {
+	entries archiveEntries
+	size    int64
+	mod     time.Time
+}
+
+// cachedEntries returns the parsed archive at path, shared with the cache:
+// callers must not modify it. A missing archive is empty.
+func cachedEntries(path string) (archiveEntries, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		archiveCache.Lock()
+		delete(archiveCache.byPath, abs)
+		archiveCache.Unlock()
+		return archiveEntries{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	archiveCache.Lock()
+	c, ok := archiveCache.byPath[abs]
+	archiveCache.Unlock()
+	if ok && c.size == info.Size() && c.mod.Equal(info.ModTime()) {
+		return c.entries, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := parseArchive(string(data))
+	cacheArchive(abs, entries, info)
+	return entries, nil
+}
+
+// cacheArchive records entries as the content of the archive at abs.
+func cacheArchive(abs string, entries archiveEntries, info fs.FileInfo) {
+	archiveCache.Lock()
+	defer archiveCache.Unlock()
+	archiveCache.byPath[abs] = cachedArchive{entries: entries, size: info.Size(), mod: info.ModTime()}
+}
+
+// loadArchive reads the archive at path into a map the caller may modify; a
+// missing archive is empty.
+func loadArchive(path string) (archiveEntries, error) {
+	entries, err := cachedEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	return maps.Clone(entries), nil
+}
+
+// storeArchive writes the archive atomically (temporary file, then rename),
+// or removes it when it has no entries left, and caches what was written.
+func storeArchive(path string, entries archiveEntries) error {
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := writeFileAtomic(path, entries.format()); err != nil {
+		return err
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		if info, err := os.Stat(path); err == nil {
+			cacheArchive(abs, entries, info)
+		}
+	}
+	return nil
+}
+
+// writeFileAtomic writes content to a temporary file next to path and renames
+// it into place, creating parent directories as needed.
+func writeFileAtomic(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// updateArchive applies fn to the archive at path under its lock.
+func updateArchive(path string, fn func(archiveEntries)) error {
+	defer lockGolden(path)()
+	entries, err := loadArchive(path)
+	if err != nil {
+		return err
+	}
+	fn(entries)
+	return storeArchive(path, entries)
+}
+
+// readArchiveEntry returns one entry; a missing archive or entry is
+// fs.ErrNotExist.
+func readArchiveEntry(path, key string) (string, error) {
+	defer lockGolden(path)()
+	entries, err := cachedEntries(path)
+	if err != nil {
+		return "", err
+	}
+	content, ok := entries[key]
+	if !ok {
+		return "", fs.ErrNotExist
+	}
+	return content, nil
+}
+
+// setArchiveEntry adds or replaces one entry.
+func setArchiveEntry(path, key, content string) error {
+	return updateArchive(path, func(e archiveEntries) { e[key] = content })
+}
+
+// deleteArchiveEntry removes one entry, and the archive once it is empty.
+func deleteArchiveEntry(path, key string) error {
+	return updateArchive(path, func(e archiveEntries) { delete(e, key) })
+}
+
+// ReadSnapshotArchive returns the snapshots stored in the txtar archive at
+// path (see SnapshotStorage) by name, for tools that render or inspect them.
+func ReadSnapshotArchive(path string) (map[string]string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	defer lockGolden(path)()
+	return loadArchive(path)
+}
+
+// --- Migration ---
+
+// MigrateToArchive moves every .golden file under dir (typically
+// testdata/snapshots) into the package archive dir/snapshots.txtar, keyed by
+// path relative to dir without the extension, and deletes the files. Entries
+// already in the archive are replaced by files of the same name. Returns the
+// number of snapshots moved. Use with SnapshotStorage = StorageArchive.
+func MigrateToArchive(dir string) (int, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".golden") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	contents := make(map[string]string, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return 0, err
+		}
+		rel, _ := filepath.Rel(dir, strings.TrimSuffix(f, ".golden"))
+		contents[filepath.ToSlash(rel)] = string(data)
+	}
+	err = updateArchive(filepath.Join(dir, packageArchive), func(e archiveEntries) {
+		for key, content := range contents {
+			e[key] = content
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil {
+			return 0, err
+		}
+	}
+	return len(files), nil
+}
+
+// MigrateFromArchive writes every entry of the txtar archive at path back as
+// <name>.golden next to it and deletes the archive; the reverse of
+// MigrateToArchive. Works for per-file archives too. Returns the number of
+// snapshots written.
+func MigrateFromArchive(path string) (int, error) {
+	defer lockGolden(path)()
+	entries, err := loadArchive(path)
+	if err != nil {
+		return 0, err
+	}
+	dir := filepath.Dir(path)
+	for key, content := range entries {
+		g := goldenRef{path: filepath.Join(dir, filepath.FromSlash(key)+".golden")}
+		if err := g.write(content); err != nil {
+			return 0, err
+		}
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,311 @@
+package tuitestkit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// withSnapshotStorage switches SnapshotStorage for the duration of the test.
+func withSnapshotStorage(t *testing.T, mode StorageMode) {
+	t.Helper()
+	orig := SnapshotStorage
+	SnapshotStorage = mode
+	t.Cleanup(func() { SnapshotStorage = orig })
+}
+
+// --- txtar format ---
+
+func TestArchive_RoundTrip(t *testing.T) {
+	entries := archiveEntries{
+		"plain":    "line 1\nline 2",
+		"trailing": "ends with newline\n",
+		"empty":    "",
+		"marker":   "-- not an entry --\n\\-- escaped already --\nok",
+	}
+	got := parseArchive(entries.format())
+	if len(got) != len(entries) {
+		t.Fatalf("got %d entries, want %d: %q", len(got), len(entries), got)
+	}
+	for name, want := range entries {
+		if got[name] != want {
+			t.Errorf("entry %q = %q, want %q", name, got[name], want)
+		}
+	}
+}
+
+func TestArchive_FormatSortsEntries(t *testing.T) {
+	out := archiveEntries{"b": "2", "c": "3", "a": "1"}.format()
+	want := archiveComment + "-- a --\n1\n-- b --\n2\n-- c --\n3\n"
+	if out != want {
+		t.Errorf("format =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestArchive_ParseIgnoresComment(t *testing.T) {
+	got := parseArchive("notes\nmore notes\n-- one --\nx\n")
+	if len(got) != 1 || got["one"] != "x" {
+		t.Errorf("parseArchive = %q, want one entry", got)
+	}
+}
+
+func TestArchive_ParallelWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.txtar")
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := setArchiveEntry(path, fmt.Sprintf("snap-%02d", i), fmt.Sprint(i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries, err := loadArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 50 {
+		t.Errorf("archive has %d entries after parallel writes, want 50", len(entries))
+	}
+}
+
+func TestArchive_DeleteLastEntryRemovesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.txtar")
+	if err := setArchiveEntry(path, "only", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := deleteArchiveEntry(path, "only"); err != nil {
+		t.Fatal(err)
+	}
+	if fileExists(path) {
+		t.Error("empty archive should be removed")
+	}
+}
+
+func TestGoldenRef_String(t *testing.T) {
+	for _, ref := range []goldenRef{
+		{path: "testdata/snapshots/list.golden"},
+		{path: "testdata/snapshots/snapshots.txtar", key: "list"},
+	} {
+		if got := parseGoldenRef(ref.String()); got != ref {
+			t.Errorf("parseGoldenRef(%q) = %+v, want %+v", ref.String(), got, ref)
+		}
+	}
+}
+
+// --- snapshots in archives ---
+
+func TestSnapshot_ArchiveStorage(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	withSnapshotStorage(t, StorageArchive)
+	UpdateSnapshots = true
+
+	SnapshotStr(t, "beta view", "beta")
+	SnapshotStr(t, "alpha view\n", "alpha")
+
+	archive := filepath.Join(dir, packageArchive)
+	data, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatalf("archive not written: %v", err)
+	}
+	if !strings.Contains(string(data), "-- alpha --\nalpha view\n\n-- beta --\nbeta view\n") {
+		t.Errorf("unexpected archive content:\n%s", data)
+	}
+	if fileExists(filepath.Join(dir, "alpha.golden")) {
+		t.Error("archive storage should not write .golden files")
+	}
+
+	UpdateSnapshots = false
+	ft := &fakeT{}
+	snapshot(ft, "beta view", "beta", 1)
+	if ft.failed {
+		t.Fatalf("matching archived snapshot failed: %s", ft.lastErr)
+	}
+	snapshot(ft, "beta changed", "beta", 1)
+	if !ft.failed || !strings.Contains(ft.lastErr, "snapshots.txtar.new#beta") {
+		t.Errorf("mismatch should point at the pending archive entry, got: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshot_ArchiveMissingEntry(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	withSnapshotStorage(t, StorageArchive)
+	writeFile(t, filepath.Join(dir, packageArchive), archiveEntries{"other": "x"}.format())
+
+	ft := &fakeT{}
+	runSnapshot(ft, "fresh", "missing", 1)
+
+	if !ft.fataled || !strings.Contains(ft.lastErr, "snapshots.txtar#missing") {
+		t.Errorf("missing entry should be fatal and name the entry, got: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshot_ArchivePerFile(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	withSnapshotStorage(t, StorageArchivePerFile)
+	CreateMissingSnapshots = true
+
+	SnapshotStr(t, "content", "per-file")
+
+	got, err := readArchiveEntry(filepath.Join(dir, "archive_test.txtar"), "per-file")
+	if err != nil || got != "content" {
+		t.Errorf("per-file archive entry = %q, %v; want content", got, err)
+	}
+}
+
+func TestSnapshotter_Storage(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	archived := &Snapshotter{Dir: dir, Update: UpdateMissing, Storage: StorageArchive}
+	files := &Snapshotter{Dir: dir, Update: UpdateMissing, Storage: StorageFiles}
+
+	archived.Snapshot(t, "in archive", "a")
+	files.Snapshot(t, "in file", "b")
+
+	if got, err := readArchiveEntry(filepath.Join(dir, packageArchive), "a"); err != nil || got != "in archive" {
+		t.Errorf("archive entry = %q, %v", got, err)
+	}
+	if !fileExists(filepath.Join(dir, "b.golden")) || fileExists(filepath.Join(dir, "a.golden")) {
+		t.Error("each Snapshotter should use its own storage mode")
+	}
+}
+
+func TestArchiveCache_SeesExternalEdits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), packageArchive)
+	if err := setArchiveEntry(path, "view", "old"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := readArchiveEntry(path, "view"); got != "old" {
+		t.Fatalf("entry = %q", got)
+	}
+	// Rewritten behind the cache's back, e.g. by snapreview or git.
+	writeFile(t, path, archiveEntries{"view": "newer content"}.format())
+	if got, _ := readArchiveEntry(path, "view"); got != "newer content" {
+		t.Errorf("entry = %q, want the edited content", got)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readArchiveEntry(path, "view"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("a removed archive should have no entries, got %v", err)
+	}
+}
+
+func TestReadSnapshotArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), packageArchive)
+	if _, err := ReadSnapshotArchive(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing archive: err = %v, want fs.ErrNotExist", err)
+	}
+	writeFile(t, path, archiveEntries{"a": "x", "b/c": "y"}.format())
+	got, err := ReadSnapshotArchive(path)
+	if err != nil || len(got) != 2 || got["b/c"] != "y" {
+		t.Errorf("ReadSnapshotArchive = %q, %v", got, err)
+	}
+}
+
+func TestPendingSnapshot_ArchiveAccept(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	withSnapshotStorage(t, StorageArchive)
+	archive := filepath.Join(dir, packageArchive)
+	writeFile(t, archive, archiveEntries{"kept": "same", "list": "old"}.format())
+
+	ft := &fakeT{}
+	snapshot(ft, "new", "list", 1)
+	snapshot(ft, "same", "kept", 1)
+
+	pending, err := FindPendingSnapshots(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Name != "snapshots.txtar#list" || pending[0].Key != "list" {
+		t.Fatalf("pending = %+v, want one archive entry", pending)
+	}
+	exp, _ := pending[0].Expected()
+	act, _ := pending[0].Actual()
+	if exp != "old" || act != "new" || pending[0].IsNew() {
+		t.Errorf("Expected/Actual/IsNew = %q/%q/%v, want old/new/false", exp, act, pending[0].IsNew())
+	}
+
+	if err := pending[0].Accept(); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := loadArchive(archive)
+	if entries["list"] != "new" || entries["kept"] != "same" {
+		t.Errorf("after Accept archive = %q", entries)
+	}
+	if fileExists(archive + pendingSuffix) {
+		t.Error("accepting the last pending entry should remove the pending archive")
+	}
+}
+
+// --- migration ---
+
+func TestMigrateArchive_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "list.golden"), "list view")
+	writeFile(t, filepath.Join(dir, "nested", "item.golden"), "item view\n")
+
+	n, err := MigrateToArchive(dir)
+	if err != nil || n != 2 {
+		t.Fatalf("MigrateToArchive = %d, %v; want 2", n, err)
+	}
+	if fileExists(filepath.Join(dir, "list.golden")) {
+		t.Error("migrated .golden files should be removed")
+	}
+	entries, _ := loadArchive(filepath.Join(dir, packageArchive))
+	if entries["list"] != "list view" || entries["nested/item"] != "item view\n" {
+		t.Errorf("archive entries = %q", entries)
+	}
+
+	n, err = MigrateFromArchive(filepath.Join(dir, packageArchive))
+	if err != nil || n != 2 {
+		t.Fatalf("MigrateFromArchive = %d, %v; want 2", n, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "nested", "item.golden"))
+	if err != nil || string(data) != "item view\n" {
+		t.Errorf("restored item.golden = %q, %v", data, err)
+	}
+	if fileExists(filepath.Join(dir, packageArchive)) {
+		t.Error("archive should be removed after migrating back")
+	}
+}
+
+// --- orphans ---
+
+func TestFindOrphanSnapshots_ArchiveEntries(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, packageArchive)
+	writeFile(t, archive, archiveEntries{"used": "a", "stale": "b"}.format())
+	used := goldenRef{path: archive, key: "used"}.String()
+	stale := goldenRef{path: archive, key: "stale"}.String()
+
+	orphans, err := findOrphanSnapshots([]string{dir}, map[string]bool{used: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 1 || orphans[0] != stale {
+		t.Fatalf("orphans = %v, want [%s]", orphans, stale)
+	}
+
+	var buf bytes.Buffer
+	if code := reportOrphanSnapshots(&buf, orphans, true, false); code != 0 {
+		t.Fatalf("prune exit code = %d: %s", code, buf.String())
+	}
+	entries, _ := loadArchive(archive)
+	if _, ok := entries["stale"]; ok || entries["used"] != "a" {
+		t.Errorf("after prune archive = %q", entries)
+	}
+}
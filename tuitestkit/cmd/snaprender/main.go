@@ -0,0 +1,205 @@
+// Command snaprender renders snapshot golden files as images, so styled
+// output can be reviewed without a terminal.
+//
+// Each .golden file is drawn with tuitestkit.ImageRenderer in whatever mode
+// it was recorded: raw and styled golden files with their colors, stripped
+// ones as plain text, image snapshots as the screen they hold, storyboards
+// frame under frame. The image is written next to the golden file
+// (view.golden → view.png) or, with -o, into a directory tree mirroring the
+// input. Each entry of a txtar snapshot archive (SNAPSHOT_STORAGE=archive)
+// becomes one image in a directory named after the archive
+// (TestX.txtar#board → TestX/board.png).
+//
+// Usage:
+//
+//	go run github.com/relux-works/skill-go-testing-tools/tuitestkit/cmd/snaprender [flags] path...
+//
+// Paths are .golden or .txtar files, or directories searched recursively.
+//
+// Flags:
+//
+//	-format     png or svg (default png)
+//	-theme      dark or light (default dark)
+//	-font-size  font size in pixels per em (default 16)
+//	-padding    border around the screen in pixels (default 8)
+//	-o          output directory
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/relux-works/skill-go-testing-tools/tuitestkit"
+)
+
+var themes = map[string]tuitestkit.ImageTheme{
+	"dark":  tuitestkit.ThemeDark,
+	"light": tuitestkit.ThemeLight,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns its exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fl := flag.NewFlagSet("snaprender", flag.ContinueOnError)
+	fl.SetOutput(stderr)
+	format := fl.String("format", "png", "image format: png or svg")
+	theme := fl.String("theme", "dark", "color theme: dark or light")
+	fontSize := fl.Float64("font-size", 16, "font size in pixels per em")
+	padding := fl.Int("padding", 8, "border around the screen in pixels")
+	out := fl.String("o", "", "output directory (default: next to each golden file)")
+	fl.Usage = func() {
+		fmt.Fprintf(stderr, "usage: snaprender [flags] path...\n\n")
+		fl.PrintDefaults()
+	}
+	if err := fl.Parse(args); err != nil {
+		return 2
+	}
+	th, ok := themes[*theme]
+	if !ok {
+		fmt.Fprintf(stderr, "snaprender: unknown theme %q (dark, light)\n", *theme)
+		return 2
+	}
+	if *format != "png" && *format != "svg" {
+		fmt.Fprintf(stderr, "snaprender: unknown format %q (png, svg)\n", *format)
+		return 2
+	}
+	if fl.NArg() == 0 {
+		fl.Usage()
+		return 2
+	}
+
+	r := tuitestkit.ImageRenderer{Theme: th, FontSize: *fontSize, Padding: *padding}
+	code := 0
+	for _, root := range fl.Args() {
+		sources, err := snapshotSources(root)
+		if err != nil {
+			fmt.Fprintf(stderr, "snaprender: %v\n", err)
+			code = 1
+			continue
+		}
+		for _, src := range sources {
+			dst := outputPath(root, src, *out, *format)
+			if err := render(r, *format, src, dst); err != nil {
+				fmt.Fprintf(stderr, "snaprender: %v\n", err)
+				code = 1
+				continue
+			}
+			fmt.Fprintln(stdout, dst)
+		}
+	}
+	return code
+}
+
+// source is one snapshot to render: a .golden file, or an entry of a txtar
+// archive.
+type source struct {
+	path    string
+	key     string // entry name; "" for a .golden file
+	content string // entry content; read from path for a .golden file
+}
+
+func (s source) String() string {
+	if s.key == "" {
+		return s.path
+	}
+	return s.path + "#" + s.key
+}
+
+// snapshotSources returns the snapshots in path: the file itself (every
+// entry of it for an archive), or the .golden files and archive entries
+// under a directory.
+func snapshotSources(path string) ([]source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return fileSources(path)
+	}
+	var sources []source
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if strings.HasSuffix(p, ".golden") || strings.HasSuffix(p, ".txtar") {
+			found, err := fileSources(p)
+			sources = append(sources, found...)
+			return err
+		}
+		return nil
+	})
+	return sources, err
+}
+
+// fileSources returns the snapshots in one file: each entry of a .txtar
+// archive, sorted by name, or the file itself.
+func fileSources(path string) ([]source, error) {
+	if !strings.HasSuffix(path, ".txtar") {
+		return []source{{path: path}}, nil
+	}
+	entries, err := tuitestkit.ReadSnapshotArchive(path)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]source, 0, len(entries))
+	for key, content := range entries {
+		sources = append(sources, source{path: path, key: key, content: content})
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].key < sources[j].key })
+	return sources, nil
+}
+
+// outputPath names the image for src found under root: next to it, or at
+// the same relative path under out. Archive entries go into a directory named
+// after the archive.
+func outputPath(root string, src source, out, format string) string {
+	name := strings.TrimSuffix(src.path, ".golden") + "." + format
+	if src.key != "" {
+		name = filepath.Join(strings.TrimSuffix(src.path, ".txtar"), filepath.FromSlash(src.key)) + "." + format
+	}
+	if out == "" {
+		return name
+	}
+	base := root
+	if strings.HasSuffix(root, ".golden") || strings.HasSuffix(root, ".txtar") {
+		base = filepath.Dir(root)
+	}
+	rel, err := filepath.Rel(base, name)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(name)
+	}
+	return filepath.Join(out, rel)
+}
+
+// render draws the snapshot src as an image at dst.
+func render(r tuitestkit.ImageRenderer, format string, src source, dst string) error {
+	content := src.content
+	if src.key == "" {
+		data, err := os.ReadFile(src.path)
+		if err != nil {
+			return err
+		}
+		content = string(data)
+	}
+	scr := tuitestkit.GoldenScreen(content)
+	var img []byte
+	var err error
+	if format == "svg" {
+		img = r.SVG(scr)
+	} else if img, err = r.PNG(scr); err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, img, 0o644)
+}
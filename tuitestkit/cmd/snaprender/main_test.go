@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/relux-works/skill-go-testing-tools/tuitestkit"
+)
+
+func writeGolden(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun_RendersDirectoryNextToGoldens(t *testing.T) {
+	dir := t.TempDir()
+	writeGolden(t, filepath.Join(dir, "board.golden"), "\x1b[31mTodo\x1b[0m")
+	writeGolden(t, filepath.Join(dir, "sub", "list.golden"), "one\ntwo")
+	writeGolden(t, filepath.Join(dir, "list.golden.new"), "pending")
+
+	var out, errOut bytes.Buffer
+	if code := run([]string{dir}, &out, &errOut); code != 0 {
+		t.Fatalf("exit code = %d: %s", code, errOut.String())
+	}
+	for _, name := range []string{"board.png", filepath.Join("sub", "list.png")} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s not written: %v", name, err)
+		}
+		if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+			t.Errorf("%s is not a PNG: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "list.golden.png")); err == nil {
+		t.Error("pending files should not be rendered")
+	}
+	tuitestkit.ContainsStr(t, out.String(), "board.png")
+}
+
+func TestRun_SVGIntoOutputDir(t *testing.T) {
+	dir, out := t.TempDir(), t.TempDir()
+	writeGolden(t, filepath.Join(dir, "sub", "view.golden"), "Todo\n-- styles --\n[1:0-4 bold fg=red]")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-format", "svg", "-theme", "light", "-o", out, dir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d: %s", code, stderr.String())
+	}
+	data, err := os.ReadFile(filepath.Join(out, "sub", "view.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := string(data)
+	if !strings.Contains(svg, `fill="#ffffff"`) || !strings.Contains(svg, `fill="#cd3131"`) {
+		t.Errorf("SVG should use the light theme's background and red:\n%s", svg)
+	}
+}
+
+func TestRun_BadFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-theme", "solarized", "."},
+		{"-format", "gif", "."},
+		{},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("run(%q) = %d, want 2", args, code)
+		}
+	}
+}
+
+func TestRun_MissingPath(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{filepath.Join(t.TempDir(), "nope")}, &stdout, &stderr); code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	tuitestkit.ContainsStr(t, stderr.String(), "snaprender:")
+}
+
+func TestRun_RendersArchiveEntries(t *testing.T) {
+	dir, out := t.TempDir(), t.TempDir()
+	archive := filepath.Join(dir, "TestX.txtar")
+	writeGolden(t, archive, "comment\n-- board --\n\x1b[31mTodo\x1b[0m\n-- sub/list --\none\ntwo\n")
+	writeGolden(t, filepath.Join(dir, "TestX.txtar.new"), "-- board --\npending\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-format", "svg", dir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d: %s", code, stderr.String())
+	}
+	board, err := os.ReadFile(filepath.Join(dir, "TestX", "board.svg"))
+	if err != nil {
+		t.Fatalf("entry not rendered: %v", err)
+	}
+	if !strings.Contains(string(board), "<desc>Todo&#xA;-- styles --&#xA;[1:0-4 fg=red]</desc>") {
+		t.Errorf("board.svg should hold only its entry:\n%s", board)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "TestX", "sub", "list.svg")); err != nil {
+		t.Errorf("nested entry not rendered: %v", err)
+	}
+
+	// The archive given directly, into an output directory.
+	if code := run([]string{"-o", out, archive}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(out, "TestX", "board.png")); err != nil {
+		t.Errorf("entry not rendered under -o: %v", err)
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	for _, tc := range []struct {
+		root string
+		src  source
+		out  string
+		want string
+	}{
+		{"snaps", source{path: "snaps/a/view.golden"}, "", "snaps/a/view.png"},
+		{"snaps", source{path: "snaps/a/view.golden"}, "imgs", "imgs/a/view.png"},
+		{"snaps/view.golden", source{path: "snaps/view.golden"}, "imgs", "imgs/view.png"},
+		{"snaps", source{path: "snaps/TestX.txtar", key: "board"}, "", "snaps/TestX/board.png"},
+		{"snaps/TestX.txtar", source{path: "snaps/TestX.txtar", key: "a/b"}, "imgs", "imgs/TestX/a/b.png"},
+	} {
+		if got := outputPath(tc.root, tc.src, tc.out, "png"); got != filepath.FromSlash(tc.want) {
+			t.Errorf("outputPath(%q, %v, %q) = %q, want %q", tc.root, tc.src, tc.out, got, tc.want)
+		}
+	}
+}
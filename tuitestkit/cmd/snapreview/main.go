@@ -0,0 +1,116 @@
+// Command snapreview reviews pending snapshot changes.
+//
+// When a snapshot comparison fails, tuitestkit writes the actual output next
+// to the golden file as <name>.golden.new. snapreview finds those files and
+// shows each one as a diff against its golden file, where it can be accepted
+// (the golden file is replaced) or rejected (the pending file is deleted).
+//
+// Usage:
+//
+//	go run github.com/relux-works/skill-go-testing-tools/tuitestkit/cmd/snapreview [flags] [dir]
+//
+// dir defaults to the current directory and is searched recursively.
+//
+// Flags:
+//
+//	-list        print pending snapshots and exit
+//	-accept-all  accept every pending snapshot without opening the TUI
+//	-reject-all  reject every pending snapshot without opening the TUI
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/relux-works/skill-go-testing-tools/tuitestkit"
+)
+
+func main() {
+	list := flag.Bool("list", false, "print pending snapshots and exit")
+	acceptAll := flag.Bool("accept-all", false, "accept every pending snapshot without opening the TUI")
+	rejectAll := flag.Bool("reject-all", false, "reject every pending snapshot without opening the TUI")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: snapreview [flags] [dir]\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *acceptAll && *rejectAll {
+		fmt.Fprintln(os.Stderr, "snapreview: -accept-all and -reject-all are mutually exclusive")
+		os.Exit(2)
+	}
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+
+	items, err := tuitestkit.FindPendingSnapshots(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "snapreview: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case *list:
+		listPending(os.Stdout, items)
+	case *acceptAll:
+		os.Exit(batch(os.Stdout, fileStore{}, items, accepted))
+	case *rejectAll:
+		os.Exit(batch(os.Stdout, fileStore{}, items, rejected))
+	case len(items) == 0:
+		fmt.Println("No pending snapshots.")
+	default:
+		p := tea.NewProgram(newModel(fileStore{}, items), tea.WithAltScreen())
+		final, err := p.Run()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "snapreview: %v\n", err)
+			os.Exit(1)
+		}
+		summarize(os.Stdout, final.(model))
+	}
+}
+
+// listPending prints one line per pending snapshot.
+func listPending(w io.Writer, items []tuitestkit.PendingSnapshot) {
+	for _, p := range items {
+		if p.IsNew() {
+			fmt.Fprintf(w, "%s (new)\n", p.Name)
+		} else {
+			fmt.Fprintln(w, p.Name)
+		}
+	}
+}
+
+// batch applies d to every item and returns the exit code: 1 if any
+// operation failed, 0 otherwise.
+func batch(w io.Writer, s store, items []tuitestkit.PendingSnapshot, d decision) int {
+	code := 0
+	for _, p := range items {
+		var err error
+		if d == accepted {
+			err = s.Accept(p)
+		} else {
+			err = s.Reject(p)
+		}
+		if err != nil {
+			fmt.Fprintf(w, "%s: %v\n", p.Name, err)
+			code = 1
+			continue
+		}
+		fmt.Fprintf(w, "%s %s\n", d, p.Name)
+	}
+	return code
+}
+
+// summarize prints the counts of a finished review session.
+func summarize(w io.Writer, m model) {
+	counts := map[decision]int{}
+	for _, s := range m.status {
+		counts[s]++
+	}
+	fmt.Fprintf(w, "%d accepted, %d rejected, %d pending\n", counts[accepted], counts[rejected], counts[undecided])
+}
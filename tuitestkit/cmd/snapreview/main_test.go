@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/relux-works/skill-go-testing-tools/tuitestkit"
+)
+
+func TestBatch_AcceptAll(t *testing.T) {
+	s := &mockStore{}
+	var out bytes.Buffer
+	code := batch(&out, s, pendingItems("a", "b"), accepted)
+
+	if code != 0 {
+		t.Errorf("exit code = %d, want 0", code)
+	}
+	tuitestkit.AssertCalledN(t, &s.MockCallRecorder, "Accept", 2)
+	tuitestkit.AssertNotCalled(t, &s.MockCallRecorder, "Reject")
+	if got, want := out.String(), "accepted a\naccepted b\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestBatch_RejectAllReportsErrors(t *testing.T) {
+	s := &mockStore{err: errors.New("boom")}
+	var out bytes.Buffer
+	code := batch(&out, s, pendingItems("a"), rejected)
+
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	tuitestkit.AssertCalledWith(t, &s.MockCallRecorder, "Reject", "a")
+	tuitestkit.ContainsStr(t, out.String(), "a: boom")
+}
+
+func TestBatch_FileStore(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "view.golden")
+	if err := os.WriteFile(golden, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(golden+".new", []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	items, err := tuitestkit.FindPendingSnapshots(dir)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("FindPendingSnapshots = %v, %v", items, err)
+	}
+
+	var out bytes.Buffer
+	if code := batch(&out, fileStore{}, items, accepted); code != 0 {
+		t.Fatalf("exit code = %d: %s", code, out.String())
+	}
+	data, _ := os.ReadFile(golden)
+	if string(data) != "new" {
+		t.Errorf("golden = %q, want %q", data, "new")
+	}
+}
+
+func TestListPending(t *testing.T) {
+	dir := t.TempDir()
+	items := []tuitestkit.PendingSnapshot{
+		{Name: "kept", GoldenPath: filepath.Join(dir, "kept.golden")},
+		{Name: "fresh", GoldenPath: filepath.Join(dir, "fresh.golden")},
+	}
+	if err := os.WriteFile(items[0].GoldenPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	listPending(&out, items)
+	if got, want := out.String(), "kept\nfresh (new)\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
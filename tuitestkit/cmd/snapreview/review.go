@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/relux-works/skill-go-testing-tools/tuitestkit"
+)
+
+// store performs the file operations behind a review decision.
+// Extracted as an interface so the model can be tested with a mock.
+type store interface {
+	// Load reads both sides of the diff; isNew reports that there is no
+	// golden file yet.
+	Load(p tuitestkit.PendingSnapshot) (expected, actual string, isNew bool, err error)
+	Accept(p tuitestkit.PendingSnapshot) error
+	Reject(p tuitestkit.PendingSnapshot) error
+}
+
+// fileStore is the real store backed by PendingSnapshot file operations.
+type fileStore struct{}
+
+func (fileStore) Load(p tuitestkit.PendingSnapshot) (string, string, bool, error) {
+	isNew := p.IsNew()
+	expected, err := p.Expected()
+	if err != nil {
+		return "", "", false, err
+	}
+	actual, err := p.Actual()
+	return expected, actual, isNew, err
+}
+
+func (fileStore) Accept(p tuitestkit.PendingSnapshot) error { return p.Accept() }
+
+func (fileStore) Reject(p tuitestkit.PendingSnapshot) error { return p.Reject() }
+
+// decision is the review outcome for one pending snapshot.
+type decision int
+
+const (
+	undecided decision = iota
+	accepted
+	rejected
+)
+
+func (d decision) String() string {
+	switch d {
+	case accepted:
+		return "accepted"
+	case rejected:
+		return "rejected"
+	}
+	return "pending"
+}
+
+// loadedMsg carries the content of the pending snapshot at index.
+type loadedMsg struct {
+	index    int
+	expected string
+	actual   string
+	isNew    bool
+	err      error
+}
+
+// decidedMsg reports the outcome of accepting or rejecting the snapshot at index.
+type decidedMsg struct {
+	index    int
+	decision decision
+	err      error
+}
+
+// model is the snapshot review screen: one pending snapshot at a time,
+// shown as a unified or side-by-side diff.
+type model struct {
+	store  store
+	items  []tuitestkit.PendingSnapshot
+	status []decision
+	isNew  []bool // known once the snapshot has been loaded
+	cursor int
+
+	expected string
+	actual   string
+	err      error
+
+	split  bool
+	scroll int
+	width  int
+	height int
+}
+
+// newModel creates a review model for items.
+func newModel(s store, items []tuitestkit.PendingSnapshot) model {
+	return model{
+		store:  s,
+		items:  items,
+		status: make([]decision, len(items)),
+		isNew:  make([]bool, len(items)),
+		width:  80,
+		height: 24,
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	return m.load(m.cursor)
+}
+
+// load returns a Cmd reading the snapshot at index.
+func (m model) load(index int) tea.Cmd {
+	if index < 0 || index >= len(m.items) {
+		return nil
+	}
+	s, p := m.store, m.items[index]
+	return func() tea.Msg {
+		expected, actual, isNew, err := s.Load(p)
+		return loadedMsg{index: index, expected: expected, actual: actual, isNew: isNew, err: err}
+	}
+}
+
+// decide returns a Cmd applying d to the snapshot under the cursor.
+func (m model) decide(d decision) tea.Cmd {
+	if len(m.items) == 0 || m.status[m.cursor] != undecided {
+		return nil
+	}
+	s, p, index := m.store, m.items[m.cursor], m.cursor
+	return func() tea.Msg {
+		var err error
+		if d == accepted {
+			err = s.Accept(p)
+		} else {
+			err = s.Reject(p)
+		}
+		return decidedMsg{index: index, decision: d, err: err}
+	}
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case loadedMsg:
+		m.isNew[msg.index] = msg.isNew
+		if msg.index == m.cursor {
+			m.expected, m.actual, m.err = msg.expected, msg.actual, msg.err
+		}
+	case decidedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.status[msg.index] = msg.decision
+		if next := m.nextUndecided(); next >= 0 {
+			return m.moveTo(next)
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "a":
+			return m, m.decide(accepted)
+		case "r", "x":
+			return m, m.decide(rejected)
+		case "right", "n", "tab":
+			return m.moveTo(m.cursor + 1)
+		case "left", "p", "shift+tab":
+			return m.moveTo(m.cursor - 1)
+		case "s":
+			m.split = !m.split
+			m.scroll = 0
+		case "down", "j":
+			m.scroll = min(m.scroll+1, max(len(m.body())-m.bodyHeight(), 0))
+		case "up", "k":
+			m.scroll = max(m.scroll-1, 0)
+		}
+	}
+	return m, nil
+}
+
+// moveTo selects the snapshot at index and loads it.
+func (m model) moveTo(index int) (tea.Model, tea.Cmd) {
+	if index < 0 || index >= len(m.items) || index == m.cursor {
+		return m, nil
+	}
+	m.cursor = index
+	m.scroll = 0
+	m.expected, m.actual, m.err = "", "", nil
+	return m, m.load(index)
+}
+
+// nextUndecided returns the first undecided snapshot after the cursor,
+// wrapping around, or -1 when everything is decided.
+func (m model) nextUndecided() int {
+	for k := 1; k <= len(m.items); k++ {
+		i := (m.cursor + k) % len(m.items)
+		if m.status[i] == undecided {
+			return i
+		}
+	}
+	return -1
+}
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true)
+	addStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	delStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	dimStyle     = lipgloss.NewStyle().Faint(true)
+	statusStyles = map[decision]lipgloss.Style{
+		undecided: lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		accepted:  lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
+		rejected:  lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+	}
+)
+
+func (m model) View() string {
+	if len(m.items) == 0 {
+		return "No pending snapshots.\n"
+	}
+
+	var b strings.Builder
+	p := m.items[m.cursor]
+	st := m.status[m.cursor]
+	label := st.String()
+	if st == undecided && m.isNew[m.cursor] {
+		label = "new"
+	}
+	fmt.Fprintf(&b, "%s  %d/%d  %s  %s\n",
+		titleStyle.Render("Snapshot review"), m.cursor+1, len(m.items), p.Name,
+		statusStyles[st].Render("["+label+"]"))
+
+	body := m.body()
+	end := min(m.scroll+m.bodyHeight(), len(body))
+	for _, line := range body[m.scroll:end] {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	mode := "side-by-side"
+	if m.split {
+		mode = "unified"
+	}
+	done := 0
+	for _, s := range m.status {
+		if s != undecided {
+			done++
+		}
+	}
+	help := fmt.Sprintf("a accept • r reject • ←/→ prev/next • s %s • ↑/↓ scroll • q quit   (%d/%d reviewed)", mode, done, len(m.items))
+	b.WriteString(dimStyle.Render(help))
+	return b.String()
+}
+
+// bodyHeight is the number of diff lines that fit between header and help.
+func (m model) bodyHeight() int {
+	return max(m.height-2, 1)
+}
+
+// body renders the diff of the current snapshot.
+func (m model) body() []string {
+	if m.err != nil {
+		return []string{delStyle.Render("error: " + m.err.Error())}
+	}
+	diff := tuitestkit.DiffLines(m.expected, m.actual)
+	if m.split {
+		return m.sideBySide(diff)
+	}
+	return unified(diff)
+}
+
+// unified renders diff lines with -/+ markers and line numbers.
+func unified(diff []tuitestkit.DiffLine) []string {
+	lines := make([]string, 0, len(diff))
+	for _, l := range diff {
+		switch l.Op {
+		case tuitestkit.DiffDelete:
+			lines = append(lines, delStyle.Render(fmt.Sprintf("-%4d  %s", l.OldLine, l.Text)))
+		case tuitestkit.DiffInsert:
+			lines = append(lines, addStyle.Render(fmt.Sprintf("+%4d  %s", l.NewLine, l.Text)))
+		default:
+			lines = append(lines, fmt.Sprintf(" %4d  %s", l.OldLine, l.Text))
+		}
+	}
+	return lines
+}
+
+// sideBySide renders expected on the left and actual on the right, pairing
+// each run of deleted lines with the inserted lines that replace it.
+func (m model) sideBySide(diff []tuitestkit.DiffLine) []string {
+	col := max((m.width-3)/2, 10)
+	cell := func(text string, style *lipgloss.Style) string {
+		text = ansi.Truncate(text, col, "…")
+		text += strings.Repeat(" ", col-ansi.StringWidth(text))
+		if style != nil {
+			return style.Render(text)
+		}
+		return text
+	}
+
+	lines := []string{cell("expected", &titleStyle) + " │ " + cell("actual", &titleStyle)}
+	for i := 0; i < len(diff); {
+		if diff[i].Op == tuitestkit.DiffEqual {
+			lines = append(lines, cell(diff[i].Text, nil)+" │ "+cell(diff[i].Text, nil))
+			i++
+			continue
+		}
+		var dels, ins []string
+		for ; i < len(diff) && diff[i].Op != tuitestkit.DiffEqual; i++ {
+			if diff[i].Op == tuitestkit.DiffDelete {
+				dels = append(dels, diff[i].Text)
+			} else {
+				ins = append(ins, diff[i].Text)
+			}
+		}
+		for k := 0; k < max(len(dels), len(ins)); k++ {
+			left, right := cell("", nil), cell("", nil)
+			if k < len(dels) {
+				left = cell(dels[k], &delStyle)
+			}
+			if k < len(ins) {
+				right = cell(ins[k], &addStyle)
+			}
+			lines = append(lines, left+" │ "+right)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/relux-works/skill-go-testing-tools/tuitestkit"
+)
+
+// mockStore serves fixed expected/actual pairs keyed by snapshot name;
+// names listed in fresh have no golden file yet.
+type mockStore struct {
+	tuitestkit.MockCallRecorder
+	content map[string][2]string
+	fresh   map[string]bool
+	err     error
+}
+
+func (s *mockStore) Load(p tuitestkit.PendingSnapshot) (string, string, bool, error) {
+	s.Record("Load", p.Name)
+	c := s.content[p.Name]
+	return c[0], c[1], s.fresh[p.Name], nil
+}
+
+func (s *mockStore) Accept(p tuitestkit.PendingSnapshot) error {
+	s.Record("Accept", p.Name)
+	return s.err
+}
+
+func (s *mockStore) Reject(p tuitestkit.PendingSnapshot) error {
+	s.Record("Reject", p.Name)
+	return s.err
+}
+
+func pendingItems(names ...string) []tuitestkit.PendingSnapshot {
+	items := make([]tuitestkit.PendingSnapshot, len(names))
+	for i, n := range names {
+		items[i] = tuitestkit.PendingSnapshot{Name: n}
+	}
+	return items
+}
+
+// run sends msgs and then feeds every resulting Cmd message back in until
+// the model settles.
+func run(m model, msgs ...tea.Msg) model {
+	m, cmds := tuitestkit.SendAndCollect(m, msgs...)
+	for len(cmds) > 0 {
+		m, cmds = tuitestkit.SendAndCollect(m, tuitestkit.ExecCmds(cmds...)...)
+	}
+	return m
+}
+
+func newTestModel(s *mockStore, names ...string) model {
+	m := newModel(s, pendingItems(names...))
+	return run(m, tuitestkit.ExecCmds(m.Init())...)
+}
+
+func TestReview_ShowsUnifiedDiff(t *testing.T) {
+	s := &mockStore{content: map[string][2]string{
+		"board": {"todo\ndone", "todo\nin progress"},
+	}}
+	m := newTestModel(s, "board")
+
+	tuitestkit.ViewContains(t, m, "1/1  board")
+	tuitestkit.ViewContains(t, m, "-   2  done")
+	tuitestkit.ViewContains(t, m, "+   2  in progress")
+	tuitestkit.ViewContains(t, m, "    1  todo")
+	tuitestkit.AssertCalledWith(t, &s.MockCallRecorder, "Load", "board")
+}
+
+func TestReview_SideBySide(t *testing.T) {
+	s := &mockStore{content: map[string][2]string{
+		"board": {"todo\ndone", "todo\nin progress"},
+	}}
+	m := newTestModel(s, "board")
+	m = run(m, tuitestkit.WindowSize(40, 10), tuitestkit.Key("s"))
+
+	tuitestkit.ViewLineContains(t, m, 1, "expected")
+	tuitestkit.ViewLineContains(t, m, 1, "actual")
+	tuitestkit.ViewLineEquals(t, m, 3, "done               │ in progress       ")
+	tuitestkit.ViewContains(t, m, "s unified")
+}
+
+func TestReview_AcceptAdvancesToNext(t *testing.T) {
+	s := &mockStore{content: map[string][2]string{
+		"a": {"old a", "new a"},
+		"b": {"old b", "new b"},
+	}}
+	m := newTestModel(s, "a", "b")
+	m = run(m, tuitestkit.Key("a"))
+
+	tuitestkit.AssertCalledWith(t, &s.MockCallRecorder, "Accept", "a")
+	tuitestkit.AssertCalledWith(t, &s.MockCallRecorder, "Load", "b")
+	tuitestkit.ViewContains(t, m, "2/2  b")
+	tuitestkit.ViewContains(t, m, "+   1  new b")
+	tuitestkit.ViewContains(t, m, "(1/2 reviewed)")
+
+	// Going back shows the decision.
+	m = run(m, tuitestkit.Key("left"))
+	tuitestkit.ViewContains(t, m, "[accepted]")
+}
+
+func TestReview_RejectAndDecidedItemsAreFinal(t *testing.T) {
+	s := &mockStore{content: map[string][2]string{"a": {"x", "y"}}}
+	m := newTestModel(s, "a")
+	m = run(m, tuitestkit.Key("r"))
+	m = run(m, tuitestkit.Key("a"))
+
+	tuitestkit.AssertCalledN(t, &s.MockCallRecorder, "Reject", 1)
+	tuitestkit.AssertNotCalled(t, &s.MockCallRecorder, "Accept")
+	tuitestkit.ViewContains(t, m, "[rejected]")
+	tuitestkit.ViewContains(t, m, "(1/1 reviewed)")
+}
+
+func TestReview_StoreErrorKeepsItemPending(t *testing.T) {
+	s := &mockStore{content: map[string][2]string{"a": {"x", "y"}}, err: errors.New("permission denied")}
+	m := newTestModel(s, "a")
+	m = run(m, tuitestkit.Key("a"))
+
+	tuitestkit.ViewContains(t, m, "error: permission denied")
+	tuitestkit.ViewContains(t, m, "(0/1 reviewed)")
+}
+
+func TestReview_ScrollAndNavigationBounds(t *testing.T) {
+	s := &mockStore{content: map[string][2]string{
+		"a": {"1\n2\n3\n4\n5", "1\n2\n3\n4\n5\n6"},
+	}}
+	m := newTestModel(s, "a")
+	m = run(m, tuitestkit.WindowSize(80, 4))
+
+	tuitestkit.ViewLineContains(t, m, 1, "1  1")
+	m = run(m, tuitestkit.Key("down"), tuitestkit.Key("down"))
+	tuitestkit.ViewLineContains(t, m, 1, "3  3")
+	m = run(m, tuitestkit.Key("down"), tuitestkit.Key("down"), tuitestkit.Key("down"), tuitestkit.Key("down"))
+	tuitestkit.ViewLineContains(t, m, 2, "+   6  6")
+	m = run(m, tuitestkit.Key("up"))
+	tuitestkit.ViewLineContains(t, m, 1, "4  4")
+
+	// Navigation past either end is a no-op.
+	m = run(m, tuitestkit.Key("left"), tuitestkit.Key("right"))
+	tuitestkit.ViewContains(t, m, "1/1  a")
+	tuitestkit.AssertCalledN(t, &s.MockCallRecorder, "Load", 1)
+}
+
+func TestReview_Quit(t *testing.T) {
+	m := newTestModel(&mockStore{}, "a")
+	_, cmds := tuitestkit.SendAndCollect(m, tuitestkit.Key("q"))
+	msgs := tuitestkit.ExecCmds(cmds...)
+	if len(msgs) != 1 {
+		t.Fatalf("q: got %d msgs, want 1", len(msgs))
+	}
+	if _, ok := msgs[0].(tea.QuitMsg); !ok {
+		t.Errorf("q: got %T, want tea.QuitMsg", msgs[0])
+	}
+}
+
+func TestReview_Empty(t *testing.T) {
+	m := newModel(&mockStore{}, nil)
+	if m.Init() != nil {
+		t.Error("Init with no items should not load anything")
+	}
+	tuitestkit.ViewContains(t, m, "No pending snapshots.")
+}
+
+func TestReview_NewSnapshotLabel(t *testing.T) {
+	s := &mockStore{
+		content: map[string][2]string{"old": {"x", "y"}, "fresh": {"", "y"}},
+		fresh:   map[string]bool{"fresh": true},
+	}
+	m := newTestModel(s, "old", "fresh")
+	tuitestkit.ViewContains(t, m, "old  [pending]")
+
+	m = run(m, tuitestkit.Key("right"))
+	tuitestkit.ViewContains(t, m, "fresh  [new]")
+	m.View()
+	if n := s.CallCount("Load"); n != 2 {
+		t.Errorf("Load called %d times, want once per snapshot", n)
+	}
+}
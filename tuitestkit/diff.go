@@ -0,0 +1,482 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DiffContext is the number of unchanged lines shown around each change in
+// the diffs printed by snapshot and view assertions. Negative values show
+// every line.
+var DiffContext = 3
+
+// DiffOp is the kind of change a DiffLine represents.
+type DiffOp int
+
+const (
+	// DiffEqual marks a line present on both sides.
+	DiffEqual DiffOp = iota
+	// DiffDelete marks a line only in the expected text.
+	DiffDelete
+	// DiffInsert marks a line only in the actual text.
+	DiffInsert
+)
+
+// DiffLine is one line of a line-by-line diff.
+// OldLine and NewLine are 1-based line numbers in the expected and actual
+// text; the side a line is missing from holds 0.
+type DiffLine struct {
+	Op      DiffOp
+	Text    string
+	OldLine int
+	NewLine int
+}
+
+// DiffLines compares expected and actual line by line and returns every line
+// of both, in order, tagged with how it changed. Within a run of changes,
+// deleted lines come before inserted ones. It is the same diff the snapshot
+// functions print on mismatch, exposed for tools that render it differently
+// (e.g. side by side).
+//
+// The diff is Myers' O(ND) algorithm in linear space, so a small change in a
+// large view costs little time and memory.
+func DiffLines(expected, actual string) []DiffLine {
+	expLines := strings.Split(expected, "\n")
+	actLines := strings.Split(actual, "\n")
+
+	// Compare lines by id rather than by string.
+	ids := make(map[string]int)
+	intern := func(lines []string) []int {
+		out := make([]int, len(lines))
+		for i, l := range lines {
+			id, ok := ids[l]
+			if !ok {
+				id = len(ids)
+				ids[l] = id
+			}
+			out[i] = id
+		}
+		return out
+	}
+	d := &differ{a: intern(expLines), b: intern(actLines)}
+	d.compare(0, len(expLines), 0, len(actLines))
+
+	lines := make([]DiffLine, len(d.ops))
+	i, j := 0, 0
+	for k, op := range d.ops {
+		switch op {
+		case DiffEqual:
+			lines[k] = DiffLine{Op: op, Text: expLines[i], OldLine: i + 1, NewLine: j + 1}
+			i++
+			j++
+		case DiffDelete:
+			lines[k] = DiffLine{Op: op, Text: expLines[i], OldLine: i + 1}
+			i++
+		case DiffInsert:
+			lines[k] = DiffLine{Op: op, Text: actLines[j], NewLine: j + 1}
+			j++
+		}
+	}
+	return deletesFirst(lines)
+}
+
+// differ computes the edit script between two sequences of line ids.
+type differ struct {
+	a, b []int
+	ops  []DiffOp
+}
+
+func (d *differ) emit(op DiffOp, n int) {
+	for ; n > 0; n-- {
+		d.ops = append(d.ops, op)
+	}
+}
+
+// compare appends the edit script turning a[a0:a1] into b[b0:b1].
+func (d *differ) compare(a0, a1, b0, b1 int) {
+	prefix := 0
+	for a0+prefix < a1 && b0+prefix < b1 && d.a[a0+prefix] == d.b[b0+prefix] {
+		prefix++
+	}
+	d.emit(DiffEqual, prefix)
+	a0, b0 = a0+prefix, b0+prefix
+
+	suffix := 0
+	for a1-suffix > a0 && b1-suffix > b0 && d.a[a1-suffix-1] == d.b[b1-suffix-1] {
+		suffix++
+	}
+	a1, b1 = a1-suffix, b1-suffix
+
+	switch {
+	case a0 == a1:
+		d.emit(DiffInsert, b1-b0)
+	case b0 == b1:
+		d.emit(DiffDelete, a1-a0)
+	default:
+		x, y, u, v, dist := d.middleSnake(a0, a1, b0, b1)
+		if dist > 1 {
+			d.compare(a0, x, b0, y)
+			d.emit(DiffEqual, u-x)
+			d.compare(u, a1, v, b1)
+		} else {
+			// One side is the other plus a single line: walk both.
+			for a0 < a1 || b0 < b1 {
+				switch {
+				case a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0]:
+					d.emit(DiffEqual, 1)
+					a0++
+					b0++
+				case a1-a0 > b1-b0:
+					d.emit(DiffDelete, 1)
+					a0++
+				default:
+					d.emit(DiffInsert, 1)
+					b0++
+				}
+			}
+		}
+	}
+	d.emit(DiffEqual, suffix)
+}
+
+// middleSnake finds the middle snake of an optimal edit path for
+// a[a0:a1] vs b[b0:b1] by searching forward from the start and backward from
+// the end at the same time. It returns the snake's start (x, y) and end
+// (u, v) in absolute indices and the length of the edit script.
+func (d *differ) middleSnake(a0, a1, b0, b1 int) (x, y, u, v, dist int) {
+	n, m := a1-a0, b1-b0
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	off := maxD + 1
+	vf := make([]int, 2*maxD+3) // furthest x on forward diagonal k = x-y
+	vr := make([]int, 2*maxD+3) // furthest x on reverse diagonal (reversed sequences)
+
+	for dd := 0; dd <= maxD; dd++ {
+		for k := -dd; k <= dd; k += 2 {
+			var x int
+			if k == -dd || (k != dd && vf[off+k-1] < vf[off+k+1]) {
+				x = vf[off+k+1]
+			} else {
+				x = vf[off+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.a[a0+x] == d.b[b0+y] {
+				x++
+				y++
+			}
+			vf[off+k] = x
+			if kr := delta - k; odd && kr >= -(dd-1) && kr <= dd-1 && x >= n-vr[off+kr] {
+				return a0 + sx, b0 + sy, a0 + x, b0 + y, 2*dd - 1
+			}
+		}
+		for k := -dd; k <= dd; k += 2 {
+			var x int
+			if k == -dd || (k != dd && vr[off+k-1] < vr[off+k+1]) {
+				x = vr[off+k+1]
+			} else {
+				x = vr[off+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.a[a1-1-x] == d.b[b1-1-y] {
+				x++
+				y++
+			}
+			vr[off+k] = x
+			if kf := delta - k; !odd && kf >= -dd && kf <= dd && vf[off+kf] >= n-x {
+				return a1 - x, b1 - y, a1 - sx, b1 - sy, 2 * dd
+			}
+		}
+	}
+	// Unreachable: the paths always meet by maxD.
+	return a0, b0, a0, b0, n + m
+}
+
+// deletesFirst reorders each run of changed lines so its deletions precede
+// its insertions, the conventional diff layout.
+func deletesFirst(lines []DiffLine) []DiffLine {
+	for start := 0; start < len(lines); {
+		if lines[start].Op == DiffEqual {
+			start++
+			continue
+		}
+		end := start
+		for end < len(lines) && lines[end].Op != DiffEqual {
+			end++
+		}
+		run := make([]DiffLine, 0, end-start)
+		for _, l := range lines[start:end] {
+			if l.Op == DiffDelete {
+				run = append(run, l)
+			}
+		}
+		for _, l := range lines[start:end] {
+			if l.Op == DiffInsert {
+				run = append(run, l)
+			}
+		}
+		copy(lines[start:end], run)
+		start = end
+	}
+	return lines
+}
+
+// DiffHunk is a run of changed lines with up to `context` unchanged lines on
+// either side, as in unified diff output. OldStart/NewStart are 1-based; for
+// an empty range they name the line before it, as in "@@ -3,0 +4,2 @@".
+type DiffHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []DiffLine
+}
+
+// DiffHunks groups the changes in lines (from DiffLines) into hunks. Changes
+// separated by at most 2*context unchanged lines share a hunk. A negative
+// context puts every line in a single hunk.
+func DiffHunks(lines []DiffLine, context int) []DiffHunk {
+	var changed []int
+	for i, l := range lines {
+		if l.Op != DiffEqual {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	if context < 0 {
+		return []DiffHunk{newDiffHunk(lines, 0, len(lines))}
+	}
+
+	var hunks []DiffHunk
+	for i := 0; i < len(changed); {
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j] <= 2*context+1 {
+			j++
+		}
+		start := max(changed[i]-context, 0)
+		end := min(changed[j]+context+1, len(lines))
+		hunks = append(hunks, newDiffHunk(lines, start, end))
+		i = j + 1
+	}
+	return hunks
+}
+
+// newDiffHunk builds the hunk for lines[start:end].
+func newDiffHunk(lines []DiffLine, start, end int) DiffHunk {
+	h := DiffHunk{Lines: lines[start:end]}
+	for _, l := range lines[:start] {
+		if l.OldLine > 0 {
+			h.OldStart = l.OldLine
+		}
+		if l.NewLine > 0 {
+			h.NewStart = l.NewLine
+		}
+	}
+	for _, l := range h.Lines {
+		if l.Op != DiffInsert {
+			h.OldLines++
+		}
+		if l.Op != DiffDelete {
+			h.NewLines++
+		}
+	}
+	if h.OldLines > 0 {
+		h.OldStart++
+	}
+	if h.NewLines > 0 {
+		h.NewStart++
+	}
+	return h
+}
+
+// unifiedDiff renders the diff between expected and actual as unified hunks
+// with DiffContext lines of context. Lines prefixed with "-" are only in
+// expected, "+" only in actual, " " in both; every line carries its line
+// number. Changed lines that pair up are followed by a marker line with "^"
+// under the characters that differ; trailing whitespace and invisible
+// characters in them are made visible (see visibleText).
+func unifiedDiff(expected, actual string) string {
+	var b strings.Builder
+
+	b.WriteString("--- expected\n")
+	b.WriteString("+++ actual\n")
+
+	for _, h := range DiffHunks(DiffLines(expected, actual), DiffContext) {
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		marks := intraLineMarks(h.Lines)
+		for i, l := range h.Lines {
+			text := l.Text
+			if m, ok := marks[i]; ok {
+				text = m.text
+			}
+			switch l.Op {
+			case DiffEqual:
+				fmt.Fprintf(&b, " %4d  %s\n", l.OldLine, text)
+			case DiffInsert:
+				fmt.Fprintf(&b, "+%4d  %s\n", l.NewLine, text)
+			case DiffDelete:
+				fmt.Fprintf(&b, "-%4d  %s\n", l.OldLine, text)
+			}
+			if m, ok := marks[i]; ok && m.carets != "" {
+				b.WriteString("       " + m.carets + "\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// lineMark is how a changed line is displayed: its text (possibly with
+// invisible characters made visible) and the marker line beneath it.
+type lineMark struct {
+	text   string
+	carets string
+}
+
+// intraLineMarks pairs the deleted and inserted lines of each run of changes
+// in order and marks the characters that differ within each pair. Unpaired
+// lines only get invisible characters made visible.
+func intraLineMarks(lines []DiffLine) map[int]lineMark {
+	marks := make(map[int]lineMark)
+	for start := 0; start < len(lines); {
+		if lines[start].Op == DiffEqual {
+			start++
+			continue
+		}
+		var dels, ins []int
+		end := start
+		for ; end < len(lines) && lines[end].Op != DiffEqual; end++ {
+			if lines[end].Op == DiffDelete {
+				dels = append(dels, end)
+			} else {
+				ins = append(ins, end)
+			}
+		}
+		for k, i := range dels {
+			if k < len(ins) {
+				old, cur := lineHighlight(lines[i].Text, lines[ins[k]].Text)
+				marks[i], marks[ins[k]] = old, cur
+			} else if hasInvisible(lines[i].Text) {
+				marks[i] = lineMark{text: visibleText(lines[i].Text)}
+			}
+		}
+		for _, i := range ins[min(len(dels), len(ins)):] {
+			if hasInvisible(lines[i].Text) {
+				marks[i] = lineMark{text: visibleText(lines[i].Text)}
+			}
+		}
+		start = end
+	}
+	return marks
+}
+
+// lineHighlight compares two versions of a line and returns each one's
+// display text and "^" markers under the part that differs from the other.
+// When the difference involves whitespace at the end of the line or
+// invisible characters, both lines are shown through visibleText so the
+// difference can be seen.
+func lineHighlight(old, cur string) (lineMark, lineMark) {
+	o, c := []rune(old), []rune(cur)
+	p, s := commonAffixes(o, c)
+	oMid, cMid := string(o[p:len(o)-s]), string(c[p:len(c)-s])
+	if hasInvisible(oMid) || hasInvisible(cMid) || trailingSpace(old) != trailingSpace(cur) {
+		o, c = []rune(visibleText(old)), []rune(visibleText(cur))
+		p, s = commonAffixes(o, c)
+	}
+	mark := func(r []rune) lineMark {
+		m := lineMark{text: string(r)}
+		if mid := string(r[p : len(r)-s]); mid != "" {
+			m.carets = strings.Repeat(" ", ansi.StringWidth(string(r[:p]))) +
+				strings.Repeat("^", max(ansi.StringWidth(mid), 1))
+		}
+		return m
+	}
+	return mark(o), mark(c)
+}
+
+// commonAffixes returns the length of the common prefix and of the common
+// suffix of a and b; they do not overlap.
+func commonAffixes(a, b []rune) (prefix, suffix int) {
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return prefix, suffix
+}
+
+// isInvisible reports whether r renders as nothing or as plain space while
+// not being a space: control characters, zero-width characters, the BOM and
+// non-breaking spaces.
+func isInvisible(r rune) bool {
+	switch r {
+	case '\u00a0', '\u00ad', '\u200b', '\u200c', '\u200d', '\u200e', '\u200f', '\u2060', '\u202f', '\ufeff':
+		return true
+	}
+	return unicode.IsControl(r)
+}
+
+// hasInvisible reports whether s contains an invisible character.
+func hasInvisible(s string) bool {
+	return strings.IndexFunc(s, isInvisible) >= 0
+}
+
+// trailingSpace returns the spaces and tabs at the end of s.
+func trailingSpace(s string) string {
+	return s[len(strings.TrimRight(s, " \t")):]
+}
+
+// visibleText makes whitespace differences readable in a diff: trailing
+// spaces become "·" and trailing tabs "→", control characters become their
+// Unicode control pictures ("␉", "␍") and other invisible characters are
+// written as <U+200B>.
+func visibleText(s string) string {
+	body := strings.TrimSuffix(s, trailingSpace(s))
+	var b strings.Builder
+	for _, r := range body {
+		switch {
+		case r < 0x20:
+			b.WriteRune(0x2400 + r)
+		case r == 0x7f:
+			b.WriteRune('␡')
+		case isInvisible(r):
+			fmt.Fprintf(&b, "<U+%04X>", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	for _, r := range s[len(body):] {
+		if r == '\t' {
+			b.WriteRune('→')
+		} else {
+			b.WriteRune('·')
+		}
+	}
+	return b.String()
+}
+
+// lineDiff shows a single-line mismatch with the differing characters
+// marked, for assertions that compare one line:
+//
+//	want  total: 3 items
+//	 got  total: 4 items
+//	             ^
+func lineDiff(want, got string) string {
+	w, g := lineHighlight(want, got)
+	var b strings.Builder
+	b.WriteString("  want  " + w.text + "\n")
+	if w.carets != "" {
+		b.WriteString("        " + w.carets + "\n")
+	}
+	b.WriteString("   got  " + g.text + "\n")
+	if g.carets != "" {
+		b.WriteString("        " + g.carets + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,204 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// lcsLen is the reference longest-common-subsequence length.
+func lcsLen(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestDiffLines_MinimalAndConsistent(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randText := func() string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return strings.Join(lines, "\n")
+	}
+	for iter := 0; iter < 500; iter++ {
+		exp, act := randText(), randText()
+		lines := DiffLines(exp, act)
+
+		var old, cur []string
+		equal := 0
+		for _, l := range lines {
+			if l.Op != DiffInsert {
+				old = append(old, l.Text)
+				if l.OldLine != len(old) {
+					t.Fatalf("%q vs %q: OldLine %d out of sequence", exp, act, l.OldLine)
+				}
+			}
+			if l.Op != DiffDelete {
+				cur = append(cur, l.Text)
+				if l.NewLine != len(cur) {
+					t.Fatalf("%q vs %q: NewLine %d out of sequence", exp, act, l.NewLine)
+				}
+			}
+			if l.Op == DiffEqual {
+				equal++
+			}
+		}
+		if strings.Join(old, "\n") != exp || strings.Join(cur, "\n") != act {
+			t.Fatalf("%q vs %q: diff does not reproduce both sides", exp, act)
+		}
+		if want := lcsLen(strings.Split(exp, "\n"), strings.Split(act, "\n")); equal != want {
+			t.Fatalf("%q vs %q: %d equal lines, want %d (not minimal)", exp, act, equal, want)
+		}
+	}
+}
+
+func TestDiffLines_LargeInput(t *testing.T) {
+	lines := make([]string, 20000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("row %d", i)
+	}
+	expected := strings.Join(lines, "\n")
+	lines[10000] = "changed"
+	got := DiffLines(expected, strings.Join(lines, "\n"))
+	if len(got) != 20001 {
+		t.Fatalf("got %d lines, want 20001", len(got))
+	}
+	if got[10000].Op != DiffDelete || got[10001].Op != DiffInsert || got[10001].Text != "changed" {
+		t.Errorf("change not found at line 10001: %+v %+v", got[10000], got[10001])
+	}
+}
+
+func TestDiffHunks(t *testing.T) {
+	var exp, act []string
+	for i := 1; i <= 20; i++ {
+		exp = append(exp, fmt.Sprint(i))
+		act = append(act, fmt.Sprint(i))
+	}
+	act[2] = "three"  // line 3
+	act[5] = "six"    // line 6: within 2*context of line 3
+	act[16] = "seven" // line 17: separate hunk
+
+	hunks := DiffHunks(DiffLines(strings.Join(exp, "\n"), strings.Join(act, "\n")), 2)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2: %+v", len(hunks), hunks)
+	}
+	h := hunks[0]
+	if h.OldStart != 1 || h.OldLines != 8 || h.NewStart != 1 || h.NewLines != 8 {
+		t.Errorf("hunk 1 = -%d,%d +%d,%d, want -1,8 +1,8", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+	h = hunks[1]
+	if h.OldStart != 15 || h.OldLines != 5 || h.NewStart != 15 || h.NewLines != 5 {
+		t.Errorf("hunk 2 = -%d,%d +%d,%d, want -15,5 +15,5", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+}
+
+func TestDiffHunks_PureInsertion(t *testing.T) {
+	hunks := DiffHunks(DiffLines("a\nb", "a\nb\nc"), 0)
+	if len(hunks) != 1 {
+		t.Fatalf("got %d hunks", len(hunks))
+	}
+	if h := hunks[0]; h.OldStart != 2 || h.OldLines != 0 || h.NewStart != 3 || h.NewLines != 1 {
+		t.Errorf("hunk = -%d,%d +%d,%d, want -2,0 +3,1", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+}
+
+func TestDiffHunks_AllContext(t *testing.T) {
+	hunks := DiffHunks(DiffLines("a\nb\nc\nd\ne", "a\nb\nX\nd\ne"), -1)
+	if len(hunks) != 1 || len(hunks[0].Lines) != 6 {
+		t.Errorf("negative context should keep every line in one hunk: %+v", hunks)
+	}
+}
+
+func TestUnifiedDiff_HidesDistantContext(t *testing.T) {
+	var exp []string
+	for i := 1; i <= 200; i++ {
+		exp = append(exp, fmt.Sprintf("line %d", i))
+	}
+	act := append([]string(nil), exp...)
+	act[99] = "line 100!"
+
+	diff := unifiedDiff(strings.Join(exp, "\n"), strings.Join(act, "\n"))
+	if !strings.Contains(diff, "@@ -97,7 +97,7 @@\n") {
+		t.Errorf("diff should have a single hunk around line 100:\n%s", diff)
+	}
+	if strings.Contains(diff, "line 1\n") || strings.Contains(diff, "line 200") {
+		t.Errorf("lines far from the change should be omitted:\n%s", diff)
+	}
+	if n := strings.Count(diff, "\n"); n > 15 {
+		t.Errorf("diff has %d lines, want it short:\n%s", n, diff)
+	}
+}
+
+func TestUnifiedDiff_IntraLineMarkers(t *testing.T) {
+	diff := unifiedDiff("total: 3 items", "total: 4 items")
+	want := "" +
+		"-   1  total: 3 items\n" +
+		"              ^\n" +
+		"+   1  total: 4 items\n" +
+		"              ^\n"
+	if !strings.Contains(diff, want) {
+		t.Errorf("diff =\n%s\nwant it to contain\n%s", diff, want)
+	}
+}
+
+func TestUnifiedDiff_TrailingWhitespace(t *testing.T) {
+	diff := unifiedDiff("status ok", "status ok  ")
+	want := "" +
+		"-   1  status ok\n" +
+		"+   1  status ok··\n" +
+		"                ^^\n"
+	if !strings.Contains(diff, want) {
+		t.Errorf("diff =\n%s\nwant it to contain\n%s", diff, want)
+	}
+}
+
+func TestUnifiedDiff_InvisibleCharacters(t *testing.T) {
+	diff := unifiedDiff("a b", "a\u00a0b\r")
+	if !strings.Contains(diff, "+   1  a<U+00A0>b␍\n") {
+		t.Errorf("invisible characters should be shown:\n%s", diff)
+	}
+	if !strings.Contains(diff, "        ^^^^^^^^^^\n") {
+		t.Errorf("marker should cover the invisible characters:\n%s", diff)
+	}
+}
+
+func TestVisibleText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain", "plain"},
+		{"pad  ", "pad··"},
+		{"tab\t", "tab→"},
+		{"in\tside", "in␉side"},
+		{"zero\u200bwidth", "zero<U+200B>width"},
+		{"\x1bx", "␛x"},
+	}
+	for _, tt := range tests {
+		if got := visibleText(tt.in); got != tt.want {
+			t.Errorf("visibleText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	got := lineDiff("Tasks (3)", "Tasks (12)")
+	want := "" +
+		"  want  Tasks (3)\n" +
+		"               ^\n" +
+		"   got  Tasks (12)\n" +
+		"               ^^\n"
+	if got != want {
+		t.Errorf("lineDiff =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -8,4 +8,9 @@
 //   - Reducer test harness: table-driven tests for pure reducers with invariant checking
 //   - Mock executor: building blocks for mocking CLI executor interfaces
 //   - View assertions: ANSI-aware helpers for asserting on View() output
+//   - Text locator: screen coordinates of rendered text, click-on-text helpers
+//   - Pane detection: bordered boxes in lipgloss layouts, pane-scoped assertions
+//   - Table parser: rendered tables back into cells, assertions by header name
+//   - Wrapped text: containment assertions that tolerate soft wraps and borders
+//   - Render environments: pinned color profile/background renderers, snapshot matrix
 package tuitestkit
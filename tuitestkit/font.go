@@ -0,0 +1,541 @@
+package tuitestkit
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// The image renderer draws text with Go Mono and Go Mono Bold (Bigelow &
+// Holmes, BSD license, see fonts/LICENSE), bundled as glyph outlines so
+// images look the same on every machine. Box drawing, block elements and
+// braille are drawn from their geometry instead, so borders join across
+// cells and spinners render although the fonts lack them.
+
+//go:embed fonts/gomono.glyphs.gz
+var goMonoRegular []byte
+
+//go:embed fonts/gomono-bold.glyphs.gz
+var goMonoBold []byte
+
+// point is a position in pixels (or font units while a font is parsed).
+type point struct{ x, y float64 }
+
+// pathSeg is one segment of an outline: 'M' (move to p[0]), 'L' (line to
+// p[0]) or 'Q' (quadratic curve through control p[0] to p[1]). Contours are
+// closed implicitly.
+type pathSeg struct {
+	op byte
+	p  [2]point
+}
+
+// outline is a set of closed contours, filled with the nonzero rule.
+type outline []pathSeg
+
+// fontFace is a parsed bundled font.
+type fontFace struct {
+	units, advance, ascent, descent float64
+	glyphs                          map[rune]outline
+}
+
+var bundledFonts struct {
+	once          sync.Once
+	regular, bold *fontFace
+}
+
+// loadFonts parses the bundled fonts on first use.
+func loadFonts() (regular, bold *fontFace) {
+	bundledFonts.once.Do(func() {
+		var err error
+		if bundledFonts.regular, err = parseFontFace(goMonoRegular); err != nil {
+			panic("tuitestkit: bundled font: " + err.Error())
+		}
+		if bundledFonts.bold, err = parseFontFace(goMonoBold); err != nil {
+			panic("tuitestkit: bundled bold font: " + err.Error())
+		}
+	})
+	return bundledFonts.regular, bundledFonts.bold
+}
+
+// parseFontFace reads the gzipped glyph format written by fonts/gen.go.
+func parseFontFace(data []byte) (*fontFace, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	f := &fontFace{glyphs: map[rune]outline{}}
+	sc := bufio.NewScanner(zr)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "units "):
+			if _, err := fmt.Sscanf(line, "units %g advance %g ascent %g descent %g", &f.units, &f.advance, &f.ascent, &f.descent); err != nil {
+				return nil, fmt.Errorf("metrics: %w", err)
+			}
+		default:
+			code, d, _ := strings.Cut(line, " ")
+			r, err := strconv.ParseUint(code, 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("glyph %q: %w", code, err)
+			}
+			path, err := parsePathData(d)
+			if err != nil {
+				return nil, fmt.Errorf("glyph U+%04X: %w", r, err)
+			}
+			f.glyphs[rune(r)] = path
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if f.units == 0 {
+		return nil, fmt.Errorf("missing metrics line")
+	}
+	return f, nil
+}
+
+// parsePathData parses the M/L/Q/Z subset of SVG path data with absolute
+// coordinates.
+func parsePathData(d string) (outline, error) {
+	var path outline
+	fields := strings.FieldsFunc(d, func(r rune) bool { return r == ' ' || r == ',' })
+	var op byte
+	var nums []float64
+	flush := func() error {
+		want := map[byte]int{'M': 2, 'L': 2, 'Q': 4}[op]
+		if op == 0 || op == 'Z' {
+			return nil
+		}
+		if len(nums) != want {
+			return fmt.Errorf("%c takes %d numbers, got %d", op, want, len(nums))
+		}
+		s := pathSeg{op: op, p: [2]point{{nums[0], nums[1]}}}
+		if op == 'Q' {
+			s.p[1] = point{nums[2], nums[3]}
+		}
+		path = append(path, s)
+		return nil
+	}
+	for _, f := range fields {
+		for f != "" {
+			if c := f[0]; c == 'M' || c == 'L' || c == 'Q' || c == 'Z' {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+				op, nums, f = c, nil, f[1:]
+				continue
+			}
+			end := strings.IndexAny(f, "MLQZ")
+			if end < 0 {
+				end = len(f)
+			}
+			n, err := strconv.ParseFloat(f[:end], 64)
+			if err != nil {
+				return nil, err
+			}
+			nums = append(nums, n)
+			f = f[end:]
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
+// glyphShape is what one cell (or a wide grapheme's cells) draws: an
+// outline in pixels relative to the top-left corner of its first cell, and
+// the coverage it is drawn with (below 1 for shade characters).
+type glyphShape struct {
+	path  outline
+	alpha float64
+}
+
+// cellMetrics are the pixel dimensions of one cell at a font size.
+type cellMetrics struct {
+	w, h     int
+	scale    float64 // pixels per font unit
+	baseline float64
+	xoff     float64 // centers the advance in the cell
+}
+
+// newCellMetrics sizes cells for the bundled font at size pixels per em,
+// with a line height of 1.2 em.
+func newCellMetrics(size float64) cellMetrics {
+	f, _ := loadFonts()
+	scale := size / f.units
+	m := cellMetrics{
+		w:     max(1, int(math.Round(f.advance*scale))),
+		h:     max(1, int(math.Round(size*1.2))),
+		scale: scale,
+	}
+	m.xoff = (float64(m.w) - f.advance*scale) / 2
+	m.baseline = math.Round((float64(m.h)-(f.ascent+f.descent)*scale)/2 + f.ascent*scale)
+	return m
+}
+
+// italicSlant is the horizontal shift per pixel of height for italic text,
+// which the bundled fonts have no face for.
+const italicSlant = 0.2
+
+// glyph returns the shape of a grapheme drawn across cells cells. Spaces
+// and empty cells have no shape; characters the font lacks are drawn as an
+// empty box.
+func (m cellMetrics) glyph(text string, bold, italic bool, cells int) glyphShape {
+	r, _ := utf8.DecodeRuneInString(text)
+	w, h := float64(m.w*cells), float64(m.h)
+	if text == "" || r == ' ' {
+		return glyphShape{}
+	}
+	if shape, ok := drawnGlyph(r, w, h); ok {
+		return shape
+	}
+	regular, boldFace := loadFonts()
+	path, ok := regular.glyphs[r]
+	if bp, found := boldFace.glyphs[r]; bold && found {
+		path, ok = bp, true
+	}
+	if !ok {
+		return glyphShape{path: tofu(w, h), alpha: 1}
+	}
+	xoff := m.xoff + float64(m.w*(cells-1))/2
+	tr := func(p point) point {
+		q := point{p.x*m.scale + xoff, p.y*m.scale + m.baseline}
+		if italic {
+			q.x += (m.baseline - q.y) * italicSlant
+		}
+		return q
+	}
+	out := make(outline, len(path))
+	for i, s := range path {
+		out[i] = pathSeg{op: s.op, p: [2]point{tr(s.p[0]), tr(s.p[1])}}
+	}
+	return glyphShape{path: out, alpha: 1}
+}
+
+// shapeBuilder collects contours for drawn glyphs.
+type shapeBuilder struct {
+	path outline
+}
+
+// poly adds a closed polygon.
+func (b *shapeBuilder) poly(pts ...point) {
+	for i, p := range pts {
+		op := byte('L')
+		if i == 0 {
+			op = 'M'
+		}
+		b.path = append(b.path, pathSeg{op: op, p: [2]point{p}})
+	}
+}
+
+// rect adds a rectangle with its edges snapped to whole pixels, so lines
+// stay sharp and join the lines of neighbouring cells.
+func (b *shapeBuilder) rect(x0, y0, x1, y1 float64) {
+	x0, y0, x1, y1 = math.Round(x0), math.Round(y0), math.Round(x1), math.Round(y1)
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	if x0 == x1 || y0 == y1 {
+		return
+	}
+	b.poly(point{x0, y0}, point{x1, y0}, point{x1, y1}, point{x0, y1})
+}
+
+// line adds a straight stroke of thickness t from p to q.
+func (b *shapeBuilder) line(p, q point, t float64) {
+	dx, dy := q.x-p.x, q.y-p.y
+	l := math.Hypot(dx, dy)
+	nx, ny := -dy/l*t/2, dx/l*t/2
+	b.poly(point{p.x + nx, p.y + ny}, point{q.x + nx, q.y + ny}, point{q.x - nx, q.y - ny}, point{p.x - nx, p.y - ny})
+}
+
+// tofu is the empty box drawn for characters the font lacks.
+func tofu(w, h float64) outline {
+	var b shapeBuilder
+	x0, y0, x1, y1 := 1.0, math.Round(h*0.15), w-1, math.Round(h*0.85)
+	b.poly(point{x0, y0}, point{x1, y0}, point{x1, y1}, point{x0, y1})
+	b.poly(point{x0 + 1, y0 + 1}, point{x0 + 1, y1 - 1}, point{x1 - 1, y1 - 1}, point{x1 - 1, y0 + 1})
+	return b.path
+}
+
+// boxArms gives the arms of the box drawing characters U+2500–U+257F as
+// weights for up, right, down and left: 0 none, 1 light, 2 heavy, 3 double.
+// "...." marks dashed lines, arcs and diagonals, drawn separately.
+var boxArms = strings.Fields(`
+	0101 0202 1010 2020 .... .... .... .... .... .... .... .... 0110 0210 0120 0220
+	0011 0012 0021 0022 1100 1200 2100 2200 1001 1002 2001 2002 1110 1210 2110 1120
+	2120 2210 1220 2220 1011 1012 2011 1021 2021 2012 1022 2022 0111 0112 0211 0212
+	0121 0122 0221 0222 1101 1102 1201 1202 2101 2102 2201 2202 1111 1112 1211 1212
+	2111 1121 2121 2112 2211 1122 1221 2212 1222 2122 2221 2222 .... .... .... ....
+	0303 3030 0310 0130 0330 0013 0031 0033 1300 3100 3300 1003 3001 3003 1310 3130
+	3330 1013 3031 3033 0313 0131 0333 1303 3101 3303 1313 3131 3333 .... .... ....
+	.... .... .... .... 0001 1000 0100 0010 0002 2000 0200 0020 0201 1020 0102 2010
+`)
+
+// drawnGlyph draws box drawing, block element and braille characters to
+// fill a w×h cell exactly.
+func drawnGlyph(r rune, w, h float64) (glyphShape, bool) {
+	var b shapeBuilder
+	alpha := 1.0
+	lt := max(1, math.Round(w/9))
+	ht := max(lt+1, math.Round(lt*2))
+	switch {
+	case r >= 0x2500 && r <= 0x257f:
+		boxGlyph(&b, r, w, h, lt, ht)
+	case r >= 0x2580 && r <= 0x259f:
+		alpha = blockGlyph(&b, r, w, h)
+	case r >= 0x2800 && r <= 0x28ff:
+		brailleGlyph(&b, r, w, h)
+	default:
+		return glyphShape{}, false
+	}
+	return glyphShape{path: b.path, alpha: alpha}, true
+}
+
+// boxGlyph draws a box drawing character from its arms: each arm runs from
+// the cell edge to the centre, where it meets the perpendicular arms.
+func boxGlyph(b *shapeBuilder, r rune, w, h, lt, ht float64) {
+	cx, cy := w/2, h/2
+	switch {
+	case r >= 0x2504 && r <= 0x250b, r >= 0x254c && r <= 0x254f:
+		dashGlyph(b, r, w, h, lt, ht)
+		return
+	case r >= 0x256d && r <= 0x2570:
+		dx := map[rune]float64{0x256d: 1, 0x256e: -1, 0x256f: -1, 0x2570: 1}[r]
+		dy := map[rune]float64{0x256d: 1, 0x256e: 1, 0x256f: -1, 0x2570: -1}[r]
+		arcGlyph(b, w, h, lt, dx, dy)
+		return
+	case r >= 0x2571 && r <= 0x2573:
+		if r != 0x2572 {
+			b.line(point{w, 0}, point{0, h}, lt)
+		}
+		if r != 0x2571 {
+			b.line(point{0, 0}, point{w, h}, lt)
+		}
+		return
+	}
+	arms := boxArms[r-0x2500]
+	thick := func(c byte) float64 {
+		switch c {
+		case '1':
+			return lt
+		case '2':
+			return ht
+		case '3':
+			return 3 * lt
+		}
+		return 0
+	}
+	g := lt // offset of each line of a double arm from the centre
+	// dirs: up, right, down, left as (horizontal?, sign); the sides of an
+	// arm are its perpendicular arms (left/right of vertical arms, up/down
+	// of horizontal ones), negative side first.
+	type dir struct {
+		horizontal bool
+		sign       float64
+		neg, pos   int
+		opposite   int
+	}
+	dirs := [4]dir{
+		{false, -1, 3, 1, 2},
+		{true, 1, 0, 2, 3},
+		{false, 1, 3, 1, 0},
+		{true, -1, 0, 2, 1},
+	}
+	for i, d := range dirs {
+		c := arms[i]
+		if c == '0' {
+			continue
+		}
+		ca, cp, edge := cx, cy, w
+		if !d.horizontal {
+			ca, cp, edge = cy, cx, h
+		}
+		if d.sign < 0 {
+			edge = 0
+		}
+		span := func(end, across0, across1 float64) {
+			if d.horizontal {
+				b.rect(end, across0, edge, across1)
+			} else {
+				b.rect(across0, end, across1, edge)
+			}
+		}
+		neg, pos, opp := arms[d.neg], arms[d.pos], arms[d.opposite]
+		if c != '3' {
+			var end float64
+			switch {
+			case neg == '3' && pos == '3' && opp != '0':
+				end = ca - (g+lt/2)*d.sign
+			case neg == '3' && pos == '3':
+				end = ca + (g-lt/2)*d.sign
+			case neg == '3' || pos == '3':
+				end = ca - (g+lt/2)*d.sign
+			default:
+				end = ca - max(thick(neg), thick(pos))/2*d.sign
+			}
+			t := thick(c)
+			span(end, cp-t/2, cp+t/2)
+			continue
+		}
+		// A double arm: one line on each side, ending where it meets the
+		// arm on its side, or at the outer corner.
+		for _, side := range []struct {
+			offset      float64
+			near, other byte
+		}{{-g, neg, pos}, {g, pos, neg}} {
+			var end float64
+			switch {
+			case side.near == '3':
+				end = ca + (g-lt/2)*d.sign
+			case side.near != '0':
+				end = ca - thick(side.near)/2*d.sign
+			case side.other == '3' || side.other == '0':
+				end = ca - (g+lt/2)*d.sign
+			default:
+				end = ca - thick(side.other)/2*d.sign
+			}
+			span(end, cp+side.offset-lt/2, cp+side.offset+lt/2)
+		}
+	}
+}
+
+// dashGlyph draws the dashed box drawing lines.
+func dashGlyph(b *shapeBuilder, r rune, w, h, lt, ht float64) {
+	n := 3.0
+	switch {
+	case r >= 0x2508 && r <= 0x250b:
+		n = 4
+	case r >= 0x254c:
+		n = 2
+	}
+	var heavy, vertical bool
+	switch r {
+	case 0x2505, 0x2509, 0x254d:
+		heavy = true
+	case 0x2506, 0x250a, 0x254e:
+		vertical = true
+	case 0x2507, 0x250b, 0x254f:
+		heavy, vertical = true, true
+	}
+	t := lt
+	if heavy {
+		t = ht
+	}
+	length := w
+	if vertical {
+		length = h
+	}
+	step := length / n
+	for i := 0.0; i < n; i++ {
+		a0, a1 := i*step+step*0.2, (i+1)*step-step*0.2
+		if vertical {
+			b.rect(w/2-t/2, a0, w/2+t/2, a1)
+		} else {
+			b.rect(a0, h/2-t/2, a1, h/2+t/2)
+		}
+	}
+}
+
+// arcGlyph draws a rounded corner joining the vertical line towards dy
+// (1 down, -1 up) with the horizontal line towards dx (1 right, -1 left).
+func arcGlyph(b *shapeBuilder, w, h, lt, dx, dy float64) {
+	vx0 := math.Round(w/2 - lt/2)
+	hy0 := math.Round(h/2 - lt/2)
+	vx1, hy1 := vx0+lt, hy0+lt
+	cx, cy := (vx0+vx1)/2, (hy0+hy1)/2
+	rad := min(w, h) / 2
+	outerX, innerX := vx0, vx1
+	if dx < 0 {
+		outerX, innerX = vx1, vx0
+	}
+	outerY, innerY := hy0, hy1
+	if dy < 0 {
+		outerY, innerY = hy1, hy0
+	}
+	xEdge, yEdge := 0.0, 0.0
+	if dx > 0 {
+		xEdge = w
+	}
+	if dy > 0 {
+		yEdge = h
+	}
+	b.path = append(b.path,
+		pathSeg{op: 'M', p: [2]point{{outerX, yEdge}}},
+		pathSeg{op: 'L', p: [2]point{{outerX, cy + rad*dy}}},
+		pathSeg{op: 'Q', p: [2]point{{outerX, outerY}, {cx + rad*dx, outerY}}},
+		pathSeg{op: 'L', p: [2]point{{xEdge, outerY}}},
+		pathSeg{op: 'L', p: [2]point{{xEdge, innerY}}},
+		pathSeg{op: 'L', p: [2]point{{cx + rad*dx, innerY}}},
+		pathSeg{op: 'Q', p: [2]point{{innerX, innerY}, {innerX, cy + rad*dy}}},
+		pathSeg{op: 'L', p: [2]point{{innerX, yEdge}}},
+	)
+}
+
+// blockGlyph draws a block element and returns its coverage: below 1 for
+// the shades ░ ▒ ▓.
+func blockGlyph(b *shapeBuilder, r rune, w, h float64) float64 {
+	switch {
+	case r == 0x2580:
+		b.rect(0, 0, w, h/2)
+	case r >= 0x2581 && r <= 0x2588:
+		b.rect(0, h-h*float64(r-0x2580)/8, w, h)
+	case r >= 0x2589 && r <= 0x258f:
+		b.rect(0, 0, w*float64(0x2590-r)/8, h)
+	case r == 0x2590:
+		b.rect(w/2, 0, w, h)
+	case r >= 0x2591 && r <= 0x2593:
+		b.rect(0, 0, w, h)
+		return float64(r-0x2590) / 4
+	case r == 0x2594:
+		b.rect(0, 0, w, h/8)
+	case r == 0x2595:
+		b.rect(w-w/8, 0, w, h)
+	default:
+		// Quadrants, as bits: upper left, upper right, lower left, lower right.
+		quads := map[rune]int{
+			0x2596: 0b0010, 0x2597: 0b0001, 0x2598: 0b1000, 0x2599: 0b1011,
+			0x259a: 0b1001, 0x259b: 0b1110, 0x259c: 0b1101, 0x259d: 0b0100,
+			0x259e: 0b0110, 0x259f: 0b0111,
+		}[r]
+		for i, q := range [][4]float64{
+			{0, 0, w / 2, h / 2}, {w / 2, 0, w, h / 2}, {0, h / 2, w / 2, h}, {w / 2, h / 2, w, h},
+		} {
+			if quads&(0b1000>>i) != 0 {
+				b.rect(q[0], q[1], q[2], q[3])
+			}
+		}
+	}
+	return 1
+}
+
+// brailleGlyph draws the raised dots of a braille pattern, two columns of
+// four.
+func brailleGlyph(b *shapeBuilder, r rune, w, h float64) {
+	bits := int(r - 0x2800)
+	// Dot n (1-based) is bit n-1: dots 1–3 and 7 in the left column, 4–6
+	// and 8 in the right one.
+	dots := [8][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 0}, {1, 1}, {1, 2}, {0, 3}, {1, 3}}
+	d := max(1, math.Round(min(w, h)/5))
+	for i, pos := range dots {
+		if bits&(1<<i) == 0 {
+			continue
+		}
+		x := w * (1 + 2*float64(pos[0])) / 4
+		y := h * (1 + 2*float64(pos[1])) / 8
+		b.rect(x-d/2, y-d/2, x+d/2, y+d/2)
+	}
+}
@@ -0,0 +1,114 @@
+package tuitestkit
+
+import (
+	"testing"
+)
+
+func TestBundledFonts(t *testing.T) {
+	regular, bold := loadFonts()
+	for name, f := range map[string]*fontFace{"regular": regular, "bold": bold} {
+		if f.units != 2048 || f.advance == 0 || f.ascent == 0 {
+			t.Errorf("%s metrics = %+v", name, f)
+		}
+		for _, r := range "Aa0~éж→" {
+			if len(f.glyphs[r]) == 0 {
+				t.Errorf("%s font lacks %q", name, r)
+			}
+		}
+	}
+}
+
+func TestParsePathData(t *testing.T) {
+	path, err := parsePathData("M0 0L10 0Q10 10 0 10ZM1 1L2 2Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := outline{
+		{op: 'M', p: [2]point{{0, 0}}},
+		{op: 'L', p: [2]point{{10, 0}}},
+		{op: 'Q', p: [2]point{{10, 10}, {0, 10}}},
+		{op: 'M', p: [2]point{{1, 1}}},
+		{op: 'L', p: [2]point{{2, 2}}},
+	}
+	if len(path) != len(want) {
+		t.Fatalf("path = %v, want %v", path, want)
+	}
+	for i := range want {
+		if path[i] != want[i] {
+			t.Errorf("segment %d = %v, want %v", i, path[i], want[i])
+		}
+	}
+	if _, err := parsePathData("M0 0L10"); err == nil {
+		t.Error("a short segment should be an error")
+	}
+}
+
+// cellCoverage rasterizes text in one cell of m and returns its coverage.
+func cellCoverage(m cellMetrics, text string) func(x, y int) float32 {
+	z := newRasterizer(m.w, m.h)
+	z.fill(m.glyph(text, false, false, 1).path, 0, 0)
+	cov := z.coverage()
+	return func(x, y int) float32 { return cov[y*m.w+x] }
+}
+
+func TestBoxDrawing_ArmsReachCellEdges(t *testing.T) {
+	if len(boxArms) != 0x80 {
+		t.Fatalf("boxArms has %d entries, want 128", len(boxArms))
+	}
+	m := newCellMetrics(16)
+	cx, cy := m.w/2, m.h/2
+	corner := cellCoverage(m, "┌")
+	if corner(m.w-1, cy) != 1 || corner(cx, m.h-1) != 1 {
+		t.Error("┌ should reach the right and bottom edges")
+	}
+	if corner(0, cy) != 0 || corner(cx, 0) != 0 {
+		t.Error("┌ should not reach the left and top edges")
+	}
+	// ═ and ║ meet in ╬ without covering the gap between their lines.
+	cross := cellCoverage(m, "╬")
+	if cross(cx, cy) != 0 {
+		t.Error("╬ should leave its centre open")
+	}
+	if cross(0, cy-1) != 1 || cross(0, cy) != 0 || cross(0, cy+1) != 1 {
+		t.Error("╬ should reach the left edge with two lines")
+	}
+}
+
+func TestDrawnGlyphs(t *testing.T) {
+	m := newCellMetrics(16)
+	full := cellCoverage(m, "█")
+	lower := cellCoverage(m, "▄")
+	for y := 0; y < m.h; y++ {
+		if full(0, y) != 1 || full(m.w-1, y) != 1 {
+			t.Fatalf("█ should fill the cell, row %d uncovered", y)
+		}
+	}
+	if lower(0, 0) != 0 || lower(0, m.h-1) != 1 {
+		t.Error("▄ should fill only the lower half")
+	}
+	if shape := m.glyph("▒", false, false, 1); shape.alpha != 0.5 {
+		t.Errorf("▒ alpha = %v, want 0.5", shape.alpha)
+	}
+	if len(m.glyph("⠿", false, false, 1).path) != 6*4 {
+		t.Error("⠿ should draw six dots")
+	}
+	if len(m.glyph("͸", false, false, 1).path) == 0 {
+		t.Error("a character the font lacks should draw a box")
+	}
+}
+
+func TestGlyph_BoldAndItalic(t *testing.T) {
+	m := newCellMetrics(16)
+	regular := m.glyph("a", false, false, 1).path
+	bold := m.glyph("a", true, false, 1).path
+	italic := m.glyph("a", false, true, 1).path
+	if len(regular) == 0 || len(bold) == 0 {
+		t.Fatal("a should have an outline")
+	}
+	if len(bold) == len(regular) && bold[0] == regular[0] {
+		t.Error("bold should use the bold face")
+	}
+	if p, q := italic[0].p[0], regular[0].p[0]; q.y < m.baseline && p.x <= q.x {
+		t.Errorf("italic should shift points above the baseline right: %v vs %v", p, q)
+	}
+}
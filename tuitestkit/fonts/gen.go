@@ -0,0 +1,88 @@
+//go:build ignore
+
+// gen.go extracts the glyph outlines of a TrueType font into the text format
+// read by the image renderer: a comment line, a metrics line, then one line
+// per character with its outline as SVG path data in font units, y pointing
+// down from the baseline:
+//
+//	# Go Mono
+//	units 2048 advance 1229 ascent 1935 descent 432
+//	0041 M327 -444L228 -123...Z
+//
+// Box drawing and block elements (U+2500–U+259F) are skipped; the renderer
+// draws them so adjacent cells join. It needs golang.org/x/image, which
+// tuitestkit does not depend on, so run it from a scratch module:
+//
+//	go run gen.go $(go env GOMODCACHE)/golang.org/x/image@<version>/font/gofont/ttfs/Go-Mono.ttf "Go Mono" | gzip -9n > gomono.glyphs.gz
+//	go run gen.go .../Go-Mono-Bold.ttf "Go Mono Bold" | gzip -9n > gomono-bold.glyphs.gz
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+func main() {
+	data, err := os.ReadFile(os.Args[1])
+	if err != nil {
+		panic(err)
+	}
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	var b sfnt.Buffer
+	units := f.UnitsPerEm()
+	ppem := fixed.I(int(units))
+	m, err := f.Metrics(&b, ppem, font.HintingNone)
+	if err != nil {
+		panic(err)
+	}
+	gi, _ := f.GlyphIndex(&b, 'M')
+	adv, _ := f.GlyphAdvance(&b, gi, ppem, font.HintingNone)
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	fmt.Fprintf(w, "# %s\n", os.Args[2])
+	fmt.Fprintf(w, "units %d advance %d ascent %d descent %d\n", units, adv.Round(), m.Ascent.Round(), m.Descent.Round())
+	pt := func(p fixed.Point26_6) string { return fmt.Sprintf("%d %d", p.X.Round(), p.Y.Round()) }
+	for r := rune(0x20); r < 0x10000; r++ {
+		if r >= 0x2500 && r < 0x25a0 || r >= 0xe000 && r < 0xf900 {
+			continue // drawn by the renderer; private use area
+		}
+		g, err := f.GlyphIndex(&b, r)
+		if err != nil || g == 0 {
+			continue
+		}
+		segs, err := f.LoadGlyph(&b, g, ppem, nil)
+		if err != nil {
+			panic(err)
+		}
+		var d []string
+		for _, s := range segs {
+			switch s.Op {
+			case sfnt.SegmentOpMoveTo:
+				if len(d) > 0 {
+					d = append(d, "Z")
+				}
+				d = append(d, "M"+pt(s.Args[0]))
+			case sfnt.SegmentOpLineTo:
+				d = append(d, "L"+pt(s.Args[0]))
+			case sfnt.SegmentOpQuadTo:
+				d = append(d, "Q"+pt(s.Args[0])+" "+pt(s.Args[1]))
+			case sfnt.SegmentOpCubeTo:
+				panic(fmt.Sprintf("U+%04X: cubic segments are not supported", r))
+			}
+		}
+		if len(d) > 0 {
+			d = append(d, "Z")
+		}
+		fmt.Fprintf(w, "%04x %s\n", r, strings.Join(d, ""))
+	}
+}
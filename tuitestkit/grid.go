@@ -0,0 +1,81 @@
+package tuitestkit
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// cellGrid is the ANSI-stripped view laid out as terminal cells.
+// Each cell holds one grapheme cluster. A wide grapheme occupies its own cell
+// plus continuation cells holding "", so column indexes line up with what the
+// terminal shows and with the X coordinate of mouse events.
+type cellGrid struct {
+	rows [][]string
+}
+
+// newCellGrid strips ANSI escape codes from view and splits it into cells.
+func newCellGrid(view string) cellGrid {
+	lines := strings.Split(StripANSI(view), "\n")
+	g := cellGrid{rows: make([][]string, len(lines))}
+	for i, line := range lines {
+		var row []string
+		state := -1
+		rest := line
+		for len(rest) > 0 {
+			var cluster string
+			var width int
+			cluster, rest, width, state = uniseg.FirstGraphemeClusterInString(rest, state)
+			if width == 0 {
+				// Zero-width clusters (stray combining marks, control chars)
+				// attach to the previous cell so they never shift columns.
+				if len(row) > 0 {
+					row[len(row)-1] += cluster
+				}
+				continue
+			}
+			row = append(row, cluster)
+			for w := 1; w < width; w++ {
+				row = append(row, "")
+			}
+		}
+		g.rows[i] = row
+	}
+	return g
+}
+
+// height returns the number of rows in the grid.
+func (g cellGrid) height() int {
+	return len(g.rows)
+}
+
+// at returns the cell at (row, col), or "" when out of range.
+func (g cellGrid) at(row, col int) string {
+	if row < 0 || row >= len(g.rows) || col < 0 || col >= len(g.rows[row]) {
+		return ""
+	}
+	return g.rows[row][col]
+}
+
+// runeAt returns the first rune of the cell at (row, col), or 0 when the
+// cell is empty, a continuation cell, or out of range.
+func (g cellGrid) runeAt(row, col int) rune {
+	for _, r := range g.at(row, col) {
+		return r
+	}
+	return 0
+}
+
+// text returns the text of cells [col0, col1) on row, skipping continuation
+// cells. Cells past the end of the line read as spaces.
+func (g cellGrid) text(row, col0, col1 int) string {
+	var b strings.Builder
+	for c := col0; c < col1; c++ {
+		if row < 0 || row >= len(g.rows) || c >= len(g.rows[row]) {
+			b.WriteByte(' ')
+			continue
+		}
+		b.WriteString(g.rows[row][c])
+	}
+	return b.String()
+}
@@ -0,0 +1,253 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// Golden files may start with a header describing how the snapshot was
+// produced. It is only written when there is something to record, so plain
+// snapshots stay plain text:
+//
+//	# tuitestkit snapshot
+//	# version: v0.4.0
+//	# size: 80x24
+//	# ansi: stripped
+//	# profile: truecolor
+//	# background: dark
+//	# scrubbers: time, uuid
+//	# mask: region 0,70 10x1
+//	# mask-fill: #
+//	# ---
+//	<view>
+const (
+	headerStart  = "# tuitestkit snapshot"
+	headerEnd    = "# ---"
+	headerPrefix = "# "
+)
+
+// ANSI modes recorded in the header.
+const (
+	ansiStripped = "stripped"
+	ansiRaw      = "raw"
+)
+
+// WithHeader records render metadata in the golden file header: tuitestkit
+// version, ANSI mode (stripped, raw, styled or image) and scrubbers, plus the window
+// size and render environment when given with WithSize / WithRenderEnv. When the
+// golden file is compared later, differing metadata fails with an explanation
+// ("recorded at 80x24 but test ran at 100x30") instead of a line diff.
+//
+// Golden files without a header are compared on content only; re-record them
+// with UPDATE_SNAPSHOTS=1 to add one.
+func WithHeader() SnapshotOption {
+	return func(c *snapshotConfig) {
+		c.withHeader = true
+	}
+}
+
+// WithSize records the window size the view was rendered at. Implies WithHeader.
+func WithSize(width, height int) SnapshotOption {
+	return func(c *snapshotConfig) {
+		c.withHeader = true
+		c.width, c.height = width, height
+	}
+}
+
+// WithRenderEnv records the color profile and background the view was
+// rendered for (see NewRenderer). Implies WithHeader.
+func WithRenderEnv(env RenderEnv) SnapshotOption {
+	return func(c *snapshotConfig) {
+		c.withHeader = true
+		c.env = &env
+	}
+}
+
+// withANSIMode prepends the option recording how the snapshot content was
+// captured; used by the Snapshot* functions.
+func withANSIMode(mode string, opts []SnapshotOption) []SnapshotOption {
+	return append([]SnapshotOption{func(c *snapshotConfig) { c.ansi = mode }}, opts...)
+}
+
+// withStripped is withANSIMode for stripped snapshots of view, keeping the
+// unstripped view for the report.
+func withStripped(view string, opts []SnapshotOption) []SnapshotOption {
+	return withANSIMode(ansiStripped, append([]SnapshotOption{withSource(view)}, opts...))
+}
+
+// withSource records the view as it was before ANSI stripping.
+func withSource(view string) SnapshotOption {
+	return func(c *snapshotConfig) { c.source = view }
+}
+
+// headerField is one "key: value" line of a golden file header.
+type headerField struct {
+	Key   string
+	Value string
+}
+
+// snapshotHeader is the ordered list of header fields. Keys may repeat
+// (e.g. one "mask" line per mask).
+type snapshotHeader []headerField
+
+// add appends a field.
+func (h *snapshotHeader) add(key, value string) {
+	*h = append(*h, headerField{Key: key, Value: value})
+}
+
+// get returns the values recorded for key, in order.
+func (h snapshotHeader) get(key string) []string {
+	var values []string
+	for _, f := range h {
+		if f.Key == key {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
+
+// encode renders the header block, or "" for an empty header.
+func (h snapshotHeader) encode() string {
+	if len(h) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(headerStart + "\n")
+	for _, f := range h {
+		b.WriteString(headerPrefix + f.Key + ": " + f.Value + "\n")
+	}
+	b.WriteString(headerEnd + "\n")
+	return b.String()
+}
+
+// parseSnapshotHeader splits a golden file into its header and content.
+// Files without a well-formed header are returned whole as content with
+// ok == false.
+func parseSnapshotHeader(data string) (h snapshotHeader, content string, ok bool) {
+	if !strings.HasPrefix(data, headerStart+"\n") {
+		return nil, data, false
+	}
+	rest := data[len(headerStart)+1:]
+	for {
+		line, after, found := strings.Cut(rest, "\n")
+		if line == headerEnd {
+			if !found {
+				after = ""
+			}
+			return h, after, true
+		}
+		key, value, isField := strings.Cut(strings.TrimPrefix(line, headerPrefix), ": ")
+		if !found || !strings.HasPrefix(line, headerPrefix) || !isField {
+			return nil, data, false
+		}
+		h.add(key, value)
+		rest = after
+	}
+}
+
+// header returns the golden file header recording c; empty when there is
+// nothing to record.
+func (c snapshotConfig) header() snapshotHeader {
+	var h snapshotHeader
+	if c.withHeader {
+		h.add("version", kitVersion())
+		if c.width > 0 || c.height > 0 {
+			h.add("size", fmt.Sprintf("%dx%d", c.width, c.height))
+		}
+		if c.ansi != "" {
+			h.add("ansi", c.ansi)
+		}
+		if c.env != nil {
+			h.add("profile", c.env.Profile.String())
+			h.add("background", c.env.Background.String())
+		}
+		names := make([]string, len(c.scrubbers))
+		for i, s := range c.scrubbers {
+			names[i] = s.Name
+		}
+		h.add("scrubbers", listOrNone(names))
+	}
+	for _, m := range c.masks {
+		h.add("mask", m.desc)
+	}
+	if len(c.masks) > 0 {
+		h.add("mask-fill", string(c.fill()))
+	}
+	return h
+}
+
+// checkSnapshotHeader compares the metadata recorded in a golden file with
+// the metadata of the current run. Fields missing on either side are not
+// compared (the golden file may predate them, or the test may not state its
+// size), except masks: a mask added or removed is always reported. The
+// version is informational and never compared.
+func checkSnapshotHeader(recorded, current snapshotHeader) error {
+	var problems []string
+	both := func(key string) (string, string, bool) {
+		r, c := recorded.get(key), current.get(key)
+		if len(r) == 0 || len(c) == 0 {
+			return "", "", false
+		}
+		rv, cv := strings.Join(r, ", "), strings.Join(c, ", ")
+		return rv, cv, rv != cv
+	}
+
+	if r, c, diff := both("size"); diff {
+		problems = append(problems, fmt.Sprintf("recorded at %s but test ran at %s", r, c))
+	}
+	if r, c, diff := both("ansi"); diff {
+		problems = append(problems, fmt.Sprintf("recorded with ANSI %s but test snapshots ANSI %s", r, c))
+	}
+	if r, c, diff := both("profile"); diff {
+		problems = append(problems, fmt.Sprintf("recorded with color profile %s but test ran with %s", r, c))
+	}
+	if r, c, diff := both("background"); diff {
+		problems = append(problems, fmt.Sprintf("recorded on a %s background but test ran on %s", r, c))
+	}
+	if r, c, diff := both("scrubbers"); diff {
+		problems = append(problems, fmt.Sprintf("recorded with scrubbers [%s] but test uses [%s]", r, c))
+	}
+	if r, c := recorded.get("mask"), current.get("mask"); strings.Join(r, "\n") != strings.Join(c, "\n") {
+		problems = append(problems, fmt.Sprintf("recorded with masks [%s] but test uses [%s]", listOrNone(r), listOrNone(c)))
+	} else if r, c, diff := both("mask-fill"); diff {
+		problems = append(problems, fmt.Sprintf("recorded with mask fill %q but test uses %q", r, c))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(problems, "; "))
+}
+
+// listOrNone joins values with ", ", or returns "none".
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+// kitModule is the import path of this package, looked up in build info.
+const kitModule = "github.com/relux-works/skill-go-testing-tools/tuitestkit"
+
+// kitVersion returns the tuitestkit module version the test binary was built
+// with: a release tag, a pseudo-version, or "(devel)" for a local checkout.
+func kitVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == kitModule {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == kitModule {
+			if dep.Replace != nil && dep.Replace.Version == "" {
+				return "(devel)"
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
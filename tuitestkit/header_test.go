@@ -0,0 +1,165 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotHeader_RoundTrip(t *testing.T) {
+	var h snapshotHeader
+	h.add("size", "80x24")
+	h.add("mask", "region 0,1 2x3")
+	h.add("mask", `pane "Clock"`)
+
+	got, content, ok := parseSnapshotHeader(h.encode() + "line 1\nline 2")
+	if !ok {
+		t.Fatal("encoded header should parse")
+	}
+	if content != "line 1\nline 2" {
+		t.Errorf("content = %q", content)
+	}
+	if len(got) != 3 || got.get("size")[0] != "80x24" || len(got.get("mask")) != 2 {
+		t.Errorf("header = %+v", got)
+	}
+}
+
+func TestParseSnapshotHeader_NoHeader(t *testing.T) {
+	for _, data := range []string{
+		"plain view",
+		"# tuitestkit snapshot\n# size: 80x24\nno terminator",
+		"# tuitestkit snapshot\nnot a field\n# ---\nview",
+		"",
+	} {
+		h, content, ok := parseSnapshotHeader(data)
+		if ok || h != nil || content != data {
+			t.Errorf("parseSnapshotHeader(%q) = %v, %q, %v; want whole content", data, h, content, ok)
+		}
+	}
+}
+
+func TestParseSnapshotHeader_EmptyContent(t *testing.T) {
+	_, content, ok := parseSnapshotHeader(headerStart + "\n" + headerEnd)
+	if !ok || content != "" {
+		t.Errorf("got %q, %v", content, ok)
+	}
+}
+
+func TestSnapshotConfig_Header(t *testing.T) {
+	cfg := newSnapshotConfig(withANSIMode(ansiRaw, []SnapshotOption{
+		WithSize(80, 24),
+		WithRenderEnv(RenderEnv{Profile: ProfileTrueColor, Background: BackgroundLight}),
+		WithScrubbers(ScrubRFC3339(), ScrubUUIDs()),
+	}))
+	want := "# tuitestkit snapshot\n" +
+		"# version: " + kitVersion() + "\n" +
+		"# size: 80x24\n" +
+		"# ansi: raw\n" +
+		"# profile: truecolor\n" +
+		"# background: light\n" +
+		"# scrubbers: time, uuid\n" +
+		"# ---\n"
+	if got := cfg.header().encode(); got != want {
+		t.Errorf("header =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCheckSnapshotHeader(t *testing.T) {
+	header := func(kv ...string) snapshotHeader {
+		var h snapshotHeader
+		for i := 0; i < len(kv); i += 2 {
+			h.add(kv[i], kv[i+1])
+		}
+		return h
+	}
+	tests := []struct {
+		name              string
+		recorded, current snapshotHeader
+		want              string
+	}{
+		{"same", header("size", "80x24"), header("size", "80x24"), ""},
+		{"size", header("size", "80x24"), header("size", "100x30"), "recorded at 80x24 but test ran at 100x30"},
+		{"size unknown", header("size", "80x24"), header("ansi", "raw"), ""},
+		{"version ignored", header("version", "v1.0.0"), header("version", "v1.1.0"), ""},
+		{"ansi", header("ansi", "stripped"), header("ansi", "raw"), "recorded with ANSI stripped but test snapshots ANSI raw"},
+		{"profile", header("profile", "truecolor"), header("profile", "ansi256"), "recorded with color profile truecolor but test ran with ansi256"},
+		{"background", header("background", "dark"), header("background", "light"), "recorded on a dark background but test ran on light"},
+		{"scrubbers", header("scrubbers", "time, uuid"), header("scrubbers", "time"), "recorded with scrubbers [time, uuid] but test uses [time]"},
+		{"mask removed", header("mask", "region 0,0 1x1"), header(), "recorded with masks [region 0,0 1x1] but test uses [none]"},
+		{"mask fill", header("mask", "m", "mask-fill", "#"), header("mask", "m", "mask-fill", "░"), `recorded with mask fill "#" but test uses "░"`},
+		{"several", header("size", "80x24", "ansi", "raw"), header("size", "90x24", "ansi", "stripped"), "recorded at 80x24 but test ran at 90x24; recorded with ANSI raw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSnapshotHeader(tt.recorded, tt.current)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshot_SizeMismatchExplained(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+	SnapshotStr(t, "narrow", "sized", WithSize(80, 24))
+
+	data, err := os.ReadFile(filepath.Join(dir, "sized.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# size: 80x24\n# ansi: stripped\n") {
+		t.Errorf("golden should record size and ANSI mode:\n%s", data)
+	}
+
+	UpdateSnapshots = false
+	ft := &fakeT{}
+	snapshot(ft, "wide", "sized", 1, withANSIMode(ansiStripped, []SnapshotOption{WithSize(100, 30)})...)
+	if !strings.Contains(ft.lastErr, `snapshot "sized" was recorded at 80x24 but test ran at 100x30`) {
+		t.Errorf("expected a size explanation, got: %s", ft.lastErr)
+	}
+	if strings.Contains(ft.lastErr, "--- expected") {
+		t.Errorf("metadata mismatch should not print a line diff: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshot_HeaderMatchComparesContent(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	writeFile(t, filepath.Join(dir, "hdr.golden"), "# tuitestkit snapshot\n# version: v0.0.1\n# size: 80x24\n# ---\nexpected")
+
+	opts := []SnapshotOption{WithSize(80, 24)}
+	ft := &fakeT{}
+	snapshot(ft, "expected", "hdr", 1, opts...)
+	if ft.failed {
+		t.Errorf("same metadata and content should match (version is ignored): %s", ft.lastErr)
+	}
+
+	snapshot(ft, "actual", "hdr", 1, opts...)
+	if !strings.Contains(ft.lastErr, "-   1  expected") || !strings.Contains(ft.lastErr, "+   1  actual") {
+		t.Errorf("content mismatch should diff the content without the header: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshot_LegacyGoldenWithoutHeader(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	writeFile(t, filepath.Join(dir, "legacy.golden"), "view")
+
+	ft := &fakeT{}
+	snapshot(ft, "view", "legacy", 1, WithHeader(), WithSize(80, 24))
+	if ft.failed {
+		t.Errorf("golden files without a header should be compared on content: %s", ft.lastErr)
+	}
+}
+
+func TestKitVersion(t *testing.T) {
+	if v := kitVersion(); v == "" || v == "unknown" {
+		t.Errorf("kitVersion() = %q, want a module version", v)
+	}
+}
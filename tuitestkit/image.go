@@ -0,0 +1,622 @@
+package tuitestkit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ansiImage is the ANSI mode of image snapshots.
+const ansiImage = "image"
+
+// Screen is a view laid out as styled terminal cells, the input of
+// ImageRenderer.
+type Screen struct {
+	scr styledScreen
+}
+
+// ParseScreen lays out a view with ANSI escape codes, as a terminal would
+// show it.
+func ParseScreen(view string) Screen {
+	return Screen{parseStyledScreen(view)}
+}
+
+// ParseStyledText reads a document in the StyledText format (the content of
+// SnapshotViewStyled golden files).
+func ParseStyledText(doc string) Screen {
+	return Screen{parseStyled(doc)}
+}
+
+// GoldenScreen reads the content of a golden file in any ANSI mode —
+// stripped, raw, styled or image — using its header when it has one.
+// Storyboard frames are stacked under their "=== frame N: label ===" lines.
+func GoldenScreen(content string) Screen {
+	h, body, _ := parseSnapshotHeader(content)
+	mode := strings.Join(h.get("ansi"), "")
+	frames := splitFrames(body)
+	if len(frames) == 1 && frames[0].label == "" {
+		return Screen{goldenView(mode, body)}
+	}
+	var scr styledScreen
+	for i, f := range frames {
+		header := parseStyledScreen(fmt.Sprintf("\x1b[2m=== frame %d: %s ===\x1b[0m", i+1, f.label))
+		scr.rows = append(scr.rows, header.rows...)
+		scr.rows = append(scr.rows, goldenView(mode, f.view).rows...)
+	}
+	return Screen{scr}
+}
+
+// goldenView lays out one view of a golden file stored in mode; golden files
+// without a header are recognised by their content.
+func goldenView(mode, view string) styledScreen {
+	switch {
+	case mode == ansiImage || (mode == "" && strings.HasPrefix(view, "<svg")):
+		if scr, ok := svgScreen(view); ok {
+			return scr
+		}
+	case mode == ansiRaw || (mode == "" && strings.Contains(view, "\x1b[")):
+		return parseStyledScreen(view)
+	}
+	return parseStyled(view)
+}
+
+// Size returns the width (of the longest row) and height of the screen in
+// cells.
+func (s Screen) Size() (cols, rows int) {
+	for _, row := range s.scr.rows {
+		cols = max(cols, len(row))
+	}
+	return cols, len(s.scr.rows)
+}
+
+// ImageTheme is the terminal color scheme of rendered images. Colors are
+// "#rrggbb".
+type ImageTheme struct {
+	Foreground string
+	Background string
+	// Palette holds the 16 basic ANSI colors: black, red, green, yellow,
+	// blue, magenta, cyan, white, then their bright variants.
+	Palette [16]string
+}
+
+// ThemeDark is the default theme: xterm colors on a dark background.
+var ThemeDark = ImageTheme{
+	Foreground: "#d4d4d4",
+	Background: "#1e1e1e",
+	Palette: [16]string{
+		"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+		"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+	},
+}
+
+// ThemeLight is a light theme with colors darkened for contrast on white.
+var ThemeLight = ImageTheme{
+	Foreground: "#333333",
+	Background: "#ffffff",
+	Palette: [16]string{
+		"#000000", "#cd3131", "#00bc00", "#949800", "#0451a5", "#bc05bc", "#0598bc", "#555555",
+		"#666666", "#cd3131", "#14ce14", "#b5ba00", "#0451a5", "#bc05bc", "#0598bc", "#a5a5a5",
+	},
+}
+
+// color resolves a cellStyle color; "" is def.
+func (th ImageTheme) color(c, def string) string {
+	if c == "" {
+		return def
+	}
+	return colorHex(c, th.Palette[:])
+}
+
+// ImageRenderer draws screens as SVG or PNG images with the bundled Go Mono
+// font, for reviewing styled output without a terminal:
+//
+//	r := tuitestkit.ImageRenderer{Theme: tuitestkit.ThemeLight, Padding: 8}
+//	png, err := r.PNG(tuitestkit.ParseScreen(m.View()))
+//
+// 256-palette and 24-bit colors are drawn as is, the 16 basic colors from
+// the theme. Bold uses the bold face; italic is slanted; faint text is
+// blended with its background; underline, strikethrough, reverse and hidden
+// are honoured. Box drawing, block and braille characters are drawn to fill
+// their cells, so borders join. The zero value renders with ThemeDark at 16
+// pixels per em.
+type ImageRenderer struct {
+	// Theme is the color scheme; the zero value means ThemeDark.
+	Theme ImageTheme
+	// FontSize is the font size in pixels per em; 0 means 16. Cells are
+	// 0.6 em wide and 1.2 em high.
+	FontSize float64
+	// Padding is the border around the cells in pixels.
+	Padding int
+	// Columns and Rows are a minimum size in cells, e.g. the terminal size
+	// the view was rendered for. The image grows to fit longer content.
+	Columns, Rows int
+}
+
+// RenderSVG renders a view with ANSI escape codes as an SVG image with the
+// default ImageRenderer.
+func RenderSVG(view string) []byte {
+	return ImageRenderer{}.SVG(ParseScreen(view))
+}
+
+// RenderPNG renders a view with ANSI escape codes as a PNG image with the
+// default ImageRenderer.
+func RenderPNG(view string) ([]byte, error) {
+	return ImageRenderer{}.PNG(ParseScreen(view))
+}
+
+// imagePaint is a screen resolved to what is drawn: rectangles and glyphs
+// at pixel positions, in painting order.
+type imagePaint struct {
+	m             cellMetrics
+	width, height int
+	background    string
+	fills         []imageRect // cell backgrounds
+	glyphs        []imageGlyph
+	lines         []imageRect // underlines and strikethroughs
+}
+
+// imageRect is a filled rectangle.
+type imageRect struct {
+	x, y, w, h int
+	color      string
+}
+
+// imageGlyph is a glyph drawn with its top-left cell corner at x, y.
+type imageGlyph struct {
+	x, y  int
+	key   glyphKey
+	color string
+}
+
+// glyphKey identifies a glyph shape; glyphs are drawn once per key.
+type glyphKey struct {
+	text         string
+	bold, italic bool
+	cells        int
+}
+
+// theme returns the theme with the zero value resolved.
+func (r ImageRenderer) theme() ImageTheme {
+	if r.Theme == (ImageTheme{}) {
+		return ThemeDark
+	}
+	return r.Theme
+}
+
+// paint lays out s.
+func (r ImageRenderer) paint(s Screen) imagePaint {
+	th := r.theme()
+	size := r.FontSize
+	if size <= 0 {
+		size = 16
+	}
+	m := newCellMetrics(size)
+	cols, rows := s.Size()
+	cols, rows = max(cols, r.Columns, 1), max(rows, r.Rows, 1)
+	p := imagePaint{
+		m:          m,
+		width:      cols*m.w + 2*r.Padding,
+		height:     rows*m.h + 2*r.Padding,
+		background: th.Background,
+	}
+	lineT := max(1, int(math.Round(size/16)))
+	for row, cells := range s.scr.rows {
+		y := r.Padding + row*m.h
+		for col := 0; col < len(cells); col++ {
+			c := cells[col]
+			x := r.Padding + col*m.w
+			st := c.style
+			fg, bg := th.color(st.fg, th.Foreground), th.color(st.bg, th.Background)
+			if st.attrs&attrReverse != 0 {
+				fg, bg = bg, fg
+			}
+			if st.attrs&attrFaint != 0 {
+				fg = mixHex(fg, bg, 0.5)
+			}
+			if bg != th.Background {
+				p.fills = appendRect(p.fills, imageRect{x, y, m.w, m.h, bg})
+			}
+			if st.attrs&attrHidden != 0 {
+				continue
+			}
+			if c.text != "" {
+				n := 1
+				for col+n < len(cells) && cells[col+n].text == "" {
+					n++
+				}
+				key := glyphKey{text: c.text, bold: st.attrs&attrBold != 0, italic: st.attrs&attrItalic != 0, cells: n}
+				if c.text != " " {
+					p.glyphs = append(p.glyphs, imageGlyph{x, y, key, fg})
+				}
+			}
+			if st.attrs&attrUnderline != 0 {
+				ul := th.color(st.ul, fg)
+				p.lines = appendRect(p.lines, imageRect{x, y + int(m.baseline) + lineT + 1, m.w, lineT, ul})
+			}
+			if st.attrs&attrStrike != 0 {
+				p.lines = appendRect(p.lines, imageRect{x, y + int(m.baseline) - int(math.Round(size*0.3)), m.w, lineT, fg})
+			}
+		}
+	}
+	return p
+}
+
+// appendRect adds r, extending the last rectangle instead when r continues
+// it on the same line in the same color.
+func appendRect(rects []imageRect, r imageRect) []imageRect {
+	if n := len(rects); n > 0 {
+		last := &rects[n-1]
+		if last.y == r.y && last.h == r.h && last.color == r.color && last.x+last.w == r.x {
+			last.w += r.w
+			return rects
+		}
+	}
+	return append(rects, r)
+}
+
+// SVG renders s as an SVG document. Glyphs are outlines, so the image looks
+// the same in every viewer; the screen's StyledText is kept in the <desc>
+// element, where GoldenScreen and image snapshot diffs read it back.
+func (r ImageRenderer) SVG(s Screen) []byte {
+	p := r.paint(s)
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", p.width, p.height, p.width, p.height)
+	b.WriteString("<desc>")
+	xml.EscapeText(&b, []byte(formatStyled(s.scr)))
+	b.WriteString("</desc>\n")
+
+	ids := map[glyphKey]int{}
+	var defs strings.Builder
+	for _, g := range p.glyphs {
+		if _, ok := ids[g.key]; ok {
+			continue
+		}
+		ids[g.key] = len(ids)
+		shape := p.m.glyph(g.key.text, g.key.bold, g.key.italic, g.key.cells)
+		opacity := ""
+		if shape.alpha < 1 {
+			opacity = ` fill-opacity="` + svgNum(shape.alpha) + `"`
+		}
+		fmt.Fprintf(&defs, `<path id="g%d"%s d="%s"/>`+"\n", ids[g.key], opacity, svgPath(shape.path))
+	}
+	if defs.Len() > 0 {
+		b.WriteString("<defs>\n" + defs.String() + "</defs>\n")
+	}
+
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`+"\n", p.width, p.height, p.background)
+	for _, f := range p.fills {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", f.x, f.y, f.w, f.h, f.color)
+	}
+	for _, g := range p.glyphs {
+		fmt.Fprintf(&b, `<use href="#g%d" x="%d" y="%d" fill="%s"/>`+"\n", ids[g.key], g.x, g.y, g.color)
+	}
+	for _, l := range p.lines {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", l.x, l.y, l.w, l.h, l.color)
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// svgPath writes an outline as SVG path data.
+func svgPath(path outline) string {
+	var b strings.Builder
+	for i, s := range path {
+		if s.op == 'M' && i > 0 {
+			b.WriteByte('Z')
+		}
+		b.WriteByte(s.op)
+		b.WriteString(svgNum(s.p[0].x) + " " + svgNum(s.p[0].y))
+		if s.op == 'Q' {
+			b.WriteString(" " + svgNum(s.p[1].x) + " " + svgNum(s.p[1].y))
+		}
+	}
+	if len(path) > 0 {
+		b.WriteByte('Z')
+	}
+	return b.String()
+}
+
+// svgNum formats a coordinate with at most two decimals.
+func svgNum(v float64) string {
+	s := strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// svgScreen reads the screen back from the <desc> of an SVG written by
+// ImageRenderer.
+func svgScreen(svg string) (styledScreen, bool) {
+	_, rest, ok := strings.Cut(svg, "<desc>")
+	if !ok {
+		return styledScreen{}, false
+	}
+	desc, _, ok := strings.Cut(rest, "</desc>")
+	if !ok {
+		return styledScreen{}, false
+	}
+	return parseStyled(html.UnescapeString(desc)), true
+}
+
+// Image renders s as an RGBA image.
+func (r ImageRenderer) Image(s Screen) *image.RGBA {
+	p := r.paint(s)
+	img := image.NewRGBA(image.Rect(0, 0, p.width, p.height))
+	fillRect(img, imageRect{0, 0, p.width, p.height, p.background})
+	for _, f := range p.fills {
+		fillRect(img, f)
+	}
+	masks := map[glyphKey]*glyphMask{}
+	for _, g := range p.glyphs {
+		mask, ok := masks[g.key]
+		if !ok {
+			mask = newGlyphMask(p.m, g.key)
+			masks[g.key] = mask
+		}
+		mask.draw(img, g.x, g.y, parseHex(g.color))
+	}
+	for _, l := range p.lines {
+		fillRect(img, l)
+	}
+	return img
+}
+
+// PNG renders s as a PNG image.
+func (r ImageRenderer) PNG(s Screen) ([]byte, error) {
+	var b bytes.Buffer
+	if err := png.Encode(&b, r.Image(s)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// fillRect paints a rectangle in one color.
+func fillRect(img *image.RGBA, r imageRect) {
+	c := parseHex(r.color)
+	for y := max(r.y, 0); y < min(r.y+r.h, img.Rect.Dy()); y++ {
+		for x := max(r.x, 0); x < min(r.x+r.w, img.Rect.Dx()); x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// glyphMask is a rasterized glyph: coverage per pixel, with a margin around
+// its cells for parts drawn outside them (accents, italic slant).
+type glyphMask struct {
+	margin, w, h int
+	cov          []float32
+}
+
+// newGlyphMask rasterizes the glyph for key.
+func newGlyphMask(m cellMetrics, key glyphKey) *glyphMask {
+	shape := m.glyph(key.text, key.bold, key.italic, key.cells)
+	margin := m.h
+	z := newRasterizer(key.cells*m.w+2*margin, m.h+2*margin)
+	z.fill(shape.path, float64(margin), float64(margin))
+	cov := z.coverage()
+	for i := range cov {
+		cov[i] *= float32(shape.alpha)
+	}
+	return &glyphMask{margin: margin, w: z.w, h: z.h, cov: cov}
+}
+
+// draw blends the glyph in color c onto img with its cell corner at x, y.
+func (g *glyphMask) draw(img *image.RGBA, x, y int, c color.RGBA) {
+	for my := 0; my < g.h; my++ {
+		py := y - g.margin + my
+		if py < 0 || py >= img.Rect.Dy() {
+			continue
+		}
+		for mx := 0; mx < g.w; mx++ {
+			a := g.cov[my*g.w+mx]
+			px := x - g.margin + mx
+			if a == 0 || px < 0 || px >= img.Rect.Dx() {
+				continue
+			}
+			d := img.RGBAAt(px, py)
+			blend := func(dst, src uint8) uint8 {
+				return uint8(math.Round(float64(dst) + (float64(src)-float64(dst))*float64(a)))
+			}
+			img.SetRGBA(px, py, color.RGBA{blend(d.R, c.R), blend(d.G, c.G), blend(d.B, c.B), 255})
+		}
+	}
+}
+
+// rasterizer computes anti-aliased coverage of outlines by accumulating the
+// signed area each edge covers in every pixel, then summing along rows.
+type rasterizer struct {
+	w, h, stride int
+	acc          []float32
+}
+
+func newRasterizer(w, h int) *rasterizer {
+	return &rasterizer{w: w, h: h, stride: w + 2, acc: make([]float32, (w+2)*h)}
+}
+
+// fill adds the contours of path, offset by dx, dy.
+func (z *rasterizer) fill(path outline, dx, dy float64) {
+	var start, cur point
+	at := func(p point) point { return point{p.x + dx, p.y + dy} }
+	for _, s := range path {
+		switch s.op {
+		case 'M':
+			z.line(cur, start)
+			start, cur = at(s.p[0]), at(s.p[0])
+		case 'L':
+			next := at(s.p[0])
+			z.line(cur, next)
+			cur = next
+		case 'Q':
+			c, end := at(s.p[0]), at(s.p[1])
+			dev := math.Hypot(cur.x-2*c.x+end.x, cur.y-2*c.y+end.y)
+			n := max(1, min(32, int(math.Ceil(math.Sqrt(dev)))))
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				u := 1 - t
+				next := point{u*u*cur.x + 2*u*t*c.x + t*t*end.x, u*u*cur.y + 2*u*t*c.y + t*t*end.y}
+				z.line(cur, next)
+				cur = next
+			}
+			cur = end
+		}
+	}
+	z.line(cur, start)
+}
+
+// line accumulates the edge from p0 to p1.
+func (z *rasterizer) line(p0, p1 point) {
+	clampX := func(p point) point {
+		p.x = min(max(p.x, 0), float64(z.w))
+		return p
+	}
+	p0, p1 = clampX(p0), clampX(p1)
+	if p0.y == p1.y {
+		return
+	}
+	dir := float32(1)
+	if p0.y > p1.y {
+		dir = -1
+		p0, p1 = p1, p0
+	}
+	dxdy := (p1.x - p0.x) / (p1.y - p0.y)
+	x := p0.x
+	if p0.y < 0 {
+		x -= p0.y * dxdy
+	}
+	for y := max(0, int(math.Floor(p0.y))); y < min(z.h, int(math.Ceil(p1.y))); y++ {
+		row := z.acc[y*z.stride : (y+1)*z.stride]
+		dy := math.Min(float64(y+1), p1.y) - math.Max(float64(y), p0.y)
+		xnext := x + dxdy*dy
+		d := float32(dy) * dir
+		x0, x1 := x, xnext
+		if x0 > x1 {
+			x0, x1 = x1, x0
+		}
+		x0floor := math.Floor(x0)
+		x0i := int(x0floor)
+		x1ceil := math.Ceil(x1)
+		x1i := int(x1ceil)
+		if x1i <= x0i+1 {
+			xmf := float32(0.5*(x+xnext) - x0floor)
+			row[x0i] += d - d*xmf
+			row[x0i+1] += d * xmf
+		} else {
+			s := 1 / (x1 - x0)
+			x0f := x0 - x0floor
+			a0 := 0.5 * s * (1 - x0f) * (1 - x0f)
+			x1f := x1 - x1ceil + 1
+			am := 0.5 * s * x1f * x1f
+			row[x0i] += d * float32(a0)
+			if x1i == x0i+2 {
+				row[x0i+1] += d * float32(1-a0-am)
+			} else {
+				a1 := s * (1.5 - x0f)
+				row[x0i+1] += d * float32(a1-a0)
+				for xi := x0i + 2; xi < x1i-1; xi++ {
+					row[xi] += d * float32(s)
+				}
+				a2 := a1 + float64(x1i-x0i-3)*s
+				row[x1i-1] += d * float32(1-a2-am)
+			}
+			row[x1i] += d * float32(am)
+		}
+		x = xnext
+	}
+}
+
+// coverage returns the coverage of each pixel, row by row, from 0 to 1.
+func (z *rasterizer) coverage() []float32 {
+	cov := make([]float32, z.w*z.h)
+	for y := 0; y < z.h; y++ {
+		var sum float32
+		for x := 0; x < z.w; x++ {
+			sum += z.acc[y*z.stride+x]
+			cov[y*z.w+x] = min(1, float32(math.Abs(float64(sum))))
+		}
+	}
+	return cov
+}
+
+// parseHex parses "#rrggbb"; anything else is black.
+func parseHex(s string) color.RGBA {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil || len(s) != 7 {
+		return color.RGBA{A: 255}
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}
+}
+
+// mixHex blends a toward b by t.
+func mixHex(a, b string, t float64) string {
+	ca, cb := parseHex(a), parseHex(b)
+	mix := func(x, y uint8) int { return int(math.Round(float64(x) + (float64(y)-float64(x))*t)) }
+	return rgbColor(mix(ca.R, cb.R), mix(ca.G, cb.G), mix(ca.B, cb.B))
+}
+
+// ImageSnapshot stores snapshots as the SVG drawn by r, so the golden file
+// pins how the view looks, not only its text and styles:
+//
+//	tuitestkit.SnapshotViewRaw(t, m, "board", tuitestkit.ImageSnapshot(tuitestkit.ImageRenderer{Theme: tuitestkit.ThemeLight}))
+//
+// Pass the view with its escape codes (the Raw or Styled functions,
+// SnapshotViewImage, or a Snapshotter with ANSIImage); masks and scrubbers
+// apply to the view before it is drawn. A mismatch is explained by text and
+// style changes read back from the images, as for styled snapshots.
+//
+// The golden file keeps its .golden name and may start with a header
+// (WithHeader) or hold one SVG per storyboard frame, so it is not always a
+// valid image on its own; render it with cmd/snaprender to look at it.
+func ImageSnapshot(r ImageRenderer) SnapshotOption {
+	return func(c *snapshotConfig) {
+		c.ansi = ansiImage
+		c.image = &r
+	}
+}
+
+// SnapshotViewImage captures model.View() as an SVG image with the default
+// ImageRenderer and compares (or updates) the golden file named `name`. Use
+// ImageSnapshot to pick another theme or font size.
+func SnapshotViewImage(t *testing.T, model tea.Model, name string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, model.View(), name, 3, withANSIMode(ansiImage, opts)...)
+}
+
+// SnapshotStrImage is SnapshotViewImage for a pre-rendered view string.
+func SnapshotStrImage(t *testing.T, view string, name string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, view, name, 3, withANSIMode(ansiImage, opts)...)
+}
+
+// renderImage draws a prepared view for an image snapshot.
+func (c snapshotConfig) renderImage(view string) string {
+	var r ImageRenderer
+	if c.image != nil {
+		r = *c.image
+	}
+	return string(r.SVG(ParseScreen(view)))
+}
+
+// imageDiff explains a mismatch between two image snapshots by the screens
+// they show.
+func imageDiff(expected, actual string) string {
+	exp, eok := svgScreen(expected)
+	act, aok := svgScreen(actual)
+	if !eok || !aok {
+		return unifiedDiff(expected, actual)
+	}
+	if diff := screenDiff(exp, act); diff != "" {
+		return diff
+	}
+	return "text and styles identical, image differs (theme, font size or renderer changed)\n"
+}
@@ -0,0 +1,149 @@
+package tuitestkit
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderSVG(t *testing.T) {
+	view := "\x1b[1;38;5;196mTodo\x1b[0m \x1b[4;48;2;0;90;200mDone\x1b[0m\n╭─╮"
+	svg := string(RenderSVG(view))
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="90" height="38"`,
+		`<rect width="90" height="38" fill="#1e1e1e"/>`,
+		`fill="#ff0000"`, // 256-palette foreground
+		`<rect x="50" y="0" width="40" height="19" fill="#005ac8"/>`, // truecolor background
+		`<desc>Todo Done&#xA;╭─╮&#xA;-- styles --&#xA;[1:0-4 bold fg=#ff0000]&#xA;[1:5-9 underline bg=#005ac8]</desc>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing %q:\n%s", want, svg)
+		}
+	}
+	if again := string(RenderSVG(view)); again != svg {
+		t.Error("SVG output should be deterministic")
+	}
+	// Each glyph shape is defined once: bold "o" is used twice.
+	if n := strings.Count(svg, `<path id=`); n != 10 {
+		t.Errorf("defined %d glyphs, want 10 (bold T o d, D o n e, ╭ ─ ╮)", n)
+	}
+}
+
+func TestImageRenderer_PNG(t *testing.T) {
+	r := ImageRenderer{Theme: ThemeLight, Padding: 4, Columns: 10, Rows: 3}
+	data, err := r.PNG(ParseScreen("\x1b[41m  \x1b[0m█\n\x1b[7mx\x1b[0m"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newCellMetrics(16)
+	if b := img.Bounds(); b.Dx() != 10*m.w+8 || b.Dy() != 3*m.h+8 {
+		t.Errorf("size = %v, want the minimum 10x3 cells plus padding", b)
+	}
+	at := func(col, row int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(4+col*m.w+m.w/2, 4+row*m.h+m.h/2)).(color.RGBA)
+	}
+	for _, tc := range []struct {
+		name     string
+		col, row int
+		want     color.RGBA
+	}{
+		{"padding-free background", 5, 2, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{"red background from the theme", 0, 0, color.RGBA{0xcd, 0x31, 0x31, 0xff}},
+		{"full block in the foreground", 2, 0, color.RGBA{0x33, 0x33, 0x33, 0xff}},
+	} {
+		if got := at(tc.col, tc.row); got != tc.want {
+			t.Errorf("%s: pixel = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+	// Reverse video: the cell is painted in the foreground color.
+	if got := at(0, 1); got == (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Error("reverse video should swap the cell background")
+	}
+}
+
+func TestImageRenderer_WideAndFaint(t *testing.T) {
+	p := ImageRenderer{}.paint(ParseScreen("日x\n\x1b[2mf\x1b[0m"))
+	if len(p.glyphs) != 3 || p.glyphs[0].key.cells != 2 || p.glyphs[1].x != 2*p.m.w {
+		t.Errorf("wide grapheme should span two cells: %+v", p.glyphs)
+	}
+	if got, want := p.glyphs[2].color, mixHex(ThemeDark.Foreground, ThemeDark.Background, 0.5); got != want {
+		t.Errorf("faint color = %s, want %s", got, want)
+	}
+}
+
+func TestGoldenScreen(t *testing.T) {
+	styled := StyledText("\x1b[1mTodo\x1b[0m")
+	image := string(RenderSVG("\x1b[1mTodo\x1b[0m"))
+	for name, content := range map[string]string{
+		"raw":    "\x1b[1mTodo\x1b[0m",
+		"styled": styled,
+		"image":  image,
+		"header": "# tuitestkit snapshot\n# ansi: image\n# ---\n" + image,
+	} {
+		if got := formatStyled(GoldenScreen(content).scr); got != styled {
+			t.Errorf("%s: GoldenScreen = %q, want %q", name, got, styled)
+		}
+	}
+
+	board := GoldenScreen(joinFrames([]storyFrame{{label: "initial", view: "a"}, {label: "down", view: "b"}}))
+	if got := board.scr.plain(); got != "=== frame 1: initial ===\na\n=== frame 2: down ===\nb" {
+		t.Errorf("storyboard frames should be stacked, got:\n%s", got)
+	}
+	if cols, rows := board.Size(); cols != 24 || rows != 4 {
+		t.Errorf("Size = %d, %d", cols, rows)
+	}
+}
+
+func TestImageSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	CreateMissingSnapshots = true
+
+	SnapshotStrImage(t, "\x1b[32mok\x1b[0m", "img")
+	data, err := os.ReadFile(filepath.Join(dir, "img.golden"))
+	if err != nil || !strings.HasPrefix(string(data), "<svg") {
+		t.Fatalf("image golden = %q, %v", data, err)
+	}
+	SnapshotStrImage(t, "\x1b[32mok\x1b[0m", "img")
+
+	ft := &fakeT{}
+	snapshot(ft, "\x1b[31mok\x1b[0m", "img", 1, withANSIMode(ansiImage, nil)...)
+	if !ft.failed || !strings.Contains(ft.lastErr, "line 1 cols 0-2: text identical, fg changed green → red") {
+		t.Errorf("image mismatch should be explained by style changes, got: %s", ft.lastErr)
+	}
+
+	ft = &fakeT{}
+	snapshot(ft, "\x1b[32mok\x1b[0m", "img", 1, ImageSnapshot(ImageRenderer{Theme: ThemeLight}))
+	if !ft.failed || !strings.Contains(ft.lastErr, "image differs (theme, font size or renderer changed)") {
+		t.Errorf("a theme change should be reported as such, got: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshotter_ANSIImage(t *testing.T) {
+	dir := t.TempDir()
+	snap := &Snapshotter{Dir: dir, Update: UpdateMissing, ANSI: ANSIImage, Options: []SnapshotOption{WithHeader()}}
+	snap.Snapshot(t, "\x1b[1mbold\x1b[0m", "view")
+
+	data, err := os.ReadFile(filepath.Join(dir, "view.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# ansi: image\n") || !strings.Contains(string(data), "[1:0-4 bold]") {
+		t.Errorf("golden file should record the image mode and the styles:\n%s", data)
+	}
+}
+
+func TestReportScreens_Image(t *testing.T) {
+	screens := reportScreens(snapshotConfig{}, ansiImage, string(RenderSVG("x")))
+	if len(screens) != 1 || !strings.HasPrefix(string(screens[0].HTML), `<img alt="" src="data:image/svg+xml;base64,`) {
+		t.Errorf("image snapshots should be embedded as data URLs: %v", screens)
+	}
+}
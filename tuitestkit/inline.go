@@ -0,0 +1,227 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// SnapshotInline compares view (after ANSI stripping) against expected, a
+// string literal written at the call site:
+//
+//	tuitestkit.SnapshotInline(t, m.View(), `
+//	┌──────┐
+//	│ Todo │
+//	└──────┘`)
+//
+// One leading newline in expected is ignored, so multi-line literals can
+// start on their own line.
+//
+// Under UPDATE_SNAPSHOTS=1 (or -update-snapshots) the literal is rewritten in
+// the calling source file instead: multi-line output becomes a raw backtick
+// literal, single-line output a quoted one, and the rest of the file is left
+// untouched. Update patterns are matched against the test name. With
+// CREATE_SNAPSHOTS=1 only empty literals ("") are filled in.
+//
+// Options such as WithScrubbers and MaskRegion apply to view before
+// comparison; inline snapshots have no header, so masks are not recorded.
+//
+// Several inline snapshots in one file are rewritten together. A call site
+// reached by several tests (e.g. a table-driven subtest loop) is only
+// rewritten when all of them produce the same output; otherwise the update
+// fails, since one literal cannot hold several snapshots — use SnapshotStrAuto
+// there instead.
+func SnapshotInline(t *testing.T, view string, expected string, opts ...SnapshotOption) {
+	t.Helper()
+	_, file, line, ok := runtime.Caller(1)
+	if !ok {
+		t.Fatalf("SnapshotInline: cannot determine caller file")
+	}
+	cfg := newSnapshotConfig(withStripped(view, opts))
+	content, err := cfg.prepare(StripANSI(view))
+	if err != nil {
+		t.Fatalf("SnapshotInline: %v", err)
+	}
+	snapshotInline(t, cfg, content, expected, file, line)
+}
+
+// snapshotInline is the implementation of SnapshotInline for the call at
+// file:line. Comparisons are added to the report under that location.
+func snapshotInline(t snapshotT, cfg snapshotConfig, content, expected, file string, line int) {
+	t.Helper()
+	ref := goldenRef{path: fmt.Sprintf("%s:%d", file, line)}
+	update := shouldUpdateSnapshot(sanitizeSnapshotName(t.Name())) || (CreateMissingSnapshots && expected == "")
+	if !update {
+		expected = strings.TrimPrefix(expected, "\n")
+		if expected != content {
+			diff := unifiedDiff(expected, content)
+			recordSnapshot(t, "inline", ref, cfg, "mismatch", expected, content, diff)
+			t.Errorf("inline snapshot mismatch at %s:%d:\n%s\nRun with UPDATE_SNAPSHOTS=1 to rewrite the expected literal.", file, line, diff)
+			return
+		}
+		recordSnapshot(t, "inline", ref, cfg, "match", expected, content, "")
+		return
+	}
+	if err := inlineEdits.add(file, line, content, t.Name()); err != nil {
+		t.Fatalf("inline snapshot at %s:%d: %v", file, line, err)
+	}
+	recordSnapshot(t, "inline", ref, cfg, "updated", "", content, "")
+}
+
+// inlineFile holds the source of a file with inline snapshots as it was when
+// the test binary was built, plus the literals rewritten so far. Line numbers
+// from runtime.Caller refer to that original source, so every rewrite is
+// applied to it rather than to the file on disk.
+type inlineFile struct {
+	orig  []byte
+	edits map[int]inlineEdit // by call line
+}
+
+// inlineEdit is the new value for one SnapshotInline literal.
+type inlineEdit struct {
+	content string
+	test    string
+}
+
+// inlineEdits collects inline snapshot rewrites for the whole test run.
+var inlineEdits = inlineRegistry{files: map[string]*inlineFile{}}
+
+type inlineRegistry struct {
+	sync.Mutex
+	files map[string]*inlineFile
+}
+
+// add records content as the new literal for the call at file:line and
+// rewrites the file with every edit recorded for it so far.
+func (r *inlineRegistry) add(file string, line int, content, test string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	f := r.files[file]
+	if f == nil {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		f = &inlineFile{orig: src, edits: map[int]inlineEdit{}}
+		r.files[file] = f
+	}
+
+	if prev, ok := f.edits[line]; ok {
+		if prev.content == content {
+			return nil
+		}
+		if prev.test != test {
+			return fmt.Errorf("call site is reached by %s and %s with different output; one literal cannot hold both (use SnapshotStrAuto instead)", prev.test, test)
+		}
+		return fmt.Errorf("call site is reached more than once by %s with different output; one literal cannot hold both", test)
+	}
+	f.edits[line] = inlineEdit{content: content, test: test}
+
+	out, err := rewriteInlineSnapshots(file, f.orig, f.edits)
+	if err != nil {
+		delete(f.edits, line)
+		return err
+	}
+	return os.WriteFile(file, out, 0o644)
+}
+
+// rewriteInlineSnapshots replaces the expected literal of each SnapshotInline
+// call in src whose line is a key of edits. Only the literals change; the
+// rest of the file is kept byte for byte, formatted or not.
+func rewriteInlineSnapshots(filename string, src []byte, edits map[int]inlineEdit) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	type splice struct {
+		start, end int
+		text       string
+	}
+	var splices []splice
+	for line, edit := range edits {
+		lit, err := findInlineLiteral(fset, file, line)
+		if err != nil {
+			return nil, err
+		}
+		splices = append(splices, splice{
+			start: fset.Position(lit.Pos()).Offset,
+			end:   fset.Position(lit.End()).Offset,
+			text:  inlineLiteral(edit.content),
+		})
+	}
+	sort.Slice(splices, func(i, j int) bool { return splices[i].start > splices[j].start })
+
+	out := append([]byte(nil), src...)
+	for _, s := range splices {
+		out = append(out[:s.start], append([]byte(s.text), out[s.end:]...)...)
+	}
+	return out, nil
+}
+
+// findInlineLiteral returns the expected literal of the SnapshotInline call
+// that spans line.
+func findInlineLiteral(fset *token.FileSet, file *ast.File, line int) (*ast.BasicLit, error) {
+	var calls []*ast.CallExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !isInlineCall(call) {
+			return true
+		}
+		if fset.Position(call.Pos()).Line <= line && line <= fset.Position(call.End()).Line {
+			calls = append(calls, call)
+		}
+		return true
+	})
+
+	switch {
+	case len(calls) == 0:
+		return nil, fmt.Errorf("no SnapshotInline call found on line %d", line)
+	case len(calls) > 1:
+		return nil, fmt.Errorf("several SnapshotInline calls on line %d; put each on its own line", line)
+	}
+	call := calls[0]
+	if len(call.Args) < 3 {
+		return nil, fmt.Errorf("SnapshotInline call on line %d has no expected argument", line)
+	}
+	lit, ok := call.Args[2].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil, fmt.Errorf("expected value on line %d is not a string literal and cannot be rewritten", line)
+	}
+	return lit, nil
+}
+
+// isInlineCall reports whether call is SnapshotInline(...) or x.SnapshotInline(...).
+func isInlineCall(call *ast.CallExpr) bool {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return fn.Name == "SnapshotInline"
+	case *ast.SelectorExpr:
+		return fn.Sel.Name == "SnapshotInline"
+	}
+	return false
+}
+
+// inlineLiteral renders content as Go source: a raw string starting on its
+// own line for multi-line content (SnapshotInline drops that first newline),
+// and a quoted string otherwise or when content cannot be a raw string.
+func inlineLiteral(content string) string {
+	if strings.Contains(content, "\n") && !strings.ContainsAny(content, "`\r") {
+		return "`\n" + content + "`"
+	}
+	if strings.HasPrefix(content, "\n") {
+		// Keep the newline SnapshotInline drops when comparing.
+		return strconv.Quote("\n" + content)
+	}
+	return strconv.Quote(content)
+}
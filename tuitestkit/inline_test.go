@@ -0,0 +1,200 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// inlineSource is a test file with three inline snapshots, on lines 10, 11-12 and 14.
+const inlineSource = `package demo
+
+import (
+	"testing"
+
+	kit "github.com/relux-works/skill-go-testing-tools/tuitestkit"
+)
+
+func TestDemo(t *testing.T) {
+	kit.SnapshotInline(t, view(), "old") // header
+	kit.SnapshotInline(t, view(),
+		"")
+	other(t)
+	kit.SnapshotInline(t, view(), "keep")
+}
+`
+
+// writeInlineSource writes inlineSource to a temp dir and returns its path.
+func writeInlineSource(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "demo_test.go")
+	writeFile(t, path, inlineSource)
+	return path
+}
+
+func readSource(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// runInline calls snapshotInline on fakeT, recovering from Fatalf panics.
+func runInline(ft *fakeT, content, expected, file string, line int) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(fatalSentinel); !ok {
+				panic(r)
+			}
+		}
+	}()
+	snapshotInline(ft, snapshotConfig{}, content, expected, file, line)
+}
+
+func TestSnapshotInline_Match(t *testing.T) {
+	SnapshotInline(t, "\x1b[1mdone\x1b[0m", "done")
+	SnapshotInline(t, "a\nb", `
+a
+b`)
+}
+
+func TestSnapshotInline_Mismatch(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	UpdateSnapshots = false
+
+	ft := &fakeT{name: "TestDemo"}
+	snapshotInline(ft, snapshotConfig{}, "new", "old", "demo_test.go", 9)
+
+	if !ft.failed {
+		t.Fatal("expected mismatch to fail")
+	}
+	for _, want := range []string{"demo_test.go:9", "-   1  old", "+   1  new", "UPDATE_SNAPSHOTS=1"} {
+		if !strings.Contains(ft.lastErr, want) {
+			t.Errorf("error should contain %q, got: %s", want, ft.lastErr)
+		}
+	}
+}
+
+func TestSnapshotInline_RewritesLiterals(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	UpdateSnapshots = true
+	path := writeInlineSource(t)
+
+	ft := &fakeT{name: "TestDemo"}
+	snapshotInline(ft, snapshotConfig{}, "new header", "old", path, 10)
+	snapshotInline(ft, snapshotConfig{}, "line 1\nline 2", "", path, 12) // reported on the literal's line
+	snapshotInline(ft, snapshotConfig{}, "keep", "keep", path, 14)
+	if ft.failed {
+		t.Fatalf("unexpected failure: %s", ft.lastErr)
+	}
+
+	want := `package demo
+
+import (
+	"testing"
+
+	kit "github.com/relux-works/skill-go-testing-tools/tuitestkit"
+)
+
+func TestDemo(t *testing.T) {
+	kit.SnapshotInline(t, view(), "new header") // header
+	kit.SnapshotInline(t, view(),
+		` + "`" + `
+line 1
+line 2` + "`" + `)
+	other(t)
+	kit.SnapshotInline(t, view(), "keep")
+}
+`
+	if got := readSource(t, path); got != want {
+		t.Errorf("rewritten source:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRewriteInlineSnapshots_KeepsUnformattedCode(t *testing.T) {
+	src := "package demo\n\nfunc TestDemo(t *testing.T) {\n  x:=map[string]int{\"a\":1,\n\t\"bb\":2}\n\tkit.SnapshotInline(t, view(x),   \"old\")\n}\n"
+	out, err := rewriteInlineSnapshots("demo_test.go", []byte(src), map[int]inlineEdit{6: {content: "new"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Replace(src, `"old"`, `"new"`, 1); string(out) != want {
+		t.Errorf("only the literal should change:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestSnapshotInline_SameSiteSeveralSubtests(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	UpdateSnapshots = true
+	path := writeInlineSource(t)
+
+	snapshotInline(&fakeT{name: "TestDemo/a"}, snapshotConfig{}, "same", "old", path, 10)
+	snapshotInline(&fakeT{name: "TestDemo/b"}, snapshotConfig{}, "same", "old", path, 10)
+	if got := readSource(t, path); !strings.Contains(got, `view(), "same")`) {
+		t.Errorf("identical output from several subtests should be written once:\n%s", got)
+	}
+
+	ft := &fakeT{name: "TestDemo/c"}
+	runInline(ft, "different", "old", path, 10)
+	if !ft.fataled {
+		t.Fatal("different output at one call site should fail")
+	}
+	if !strings.Contains(ft.lastErr, "TestDemo/a") || !strings.Contains(ft.lastErr, "TestDemo/c") {
+		t.Errorf("error should name both tests, got: %s", ft.lastErr)
+	}
+	if got := readSource(t, path); !strings.Contains(got, `view(), "same")`) {
+		t.Errorf("conflicting output must not overwrite the literal:\n%s", got)
+	}
+}
+
+func TestSnapshotInline_CreateMissingOnlyFillsEmpty(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	UpdateSnapshots = false
+	CreateMissingSnapshots = true
+	path := writeInlineSource(t)
+
+	ft := &fakeT{name: "TestDemo"}
+	snapshotInline(ft, snapshotConfig{}, "filled", "", path, 12)
+	snapshotInline(ft, snapshotConfig{}, "changed", "old", path, 10)
+
+	got := readSource(t, path)
+	if !strings.Contains(got, `"filled"`) {
+		t.Errorf("empty literal should be filled:\n%s", got)
+	}
+	if !strings.Contains(got, `"old"`) {
+		t.Errorf("non-empty literal must not be rewritten:\n%s", got)
+	}
+	if !ft.failed {
+		t.Error("mismatch on a non-empty literal should still fail")
+	}
+}
+
+func TestSnapshotInline_NonLiteralExpected(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	UpdateSnapshots = true
+	path := filepath.Join(t.TempDir(), "x_test.go")
+	writeFile(t, path, "package x\n\nfunc f() {\n\tSnapshotInline(t, v, want, opt)\n}\n")
+
+	ft := &fakeT{name: "TestX"}
+	runInline(ft, "v", "w", path, 4)
+	if !ft.fataled || !strings.Contains(ft.lastErr, "not a string literal") {
+		t.Errorf("expected a not-a-literal failure, got: %s", ft.lastErr)
+	}
+}
+
+func TestInlineLiteral(t *testing.T) {
+	tests := []struct{ content, want string }{
+		{"one line", `"one line"`},
+		{"a\nb", "`\na\nb`"},
+		{"has `tick`\nx", "\"has `tick`\\nx\""},
+		{"\nleading", "`\n\nleading`"},
+		{"", `""`},
+	}
+	for _, tt := range tests {
+		if got := inlineLiteral(tt.content); got != tt.want {
+			t.Errorf("inlineLiteral(%q) = %s, want %s", tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Region is a rectangular area of the rendered screen, measured in terminal
+// cells. Row and Col are zero-based, matching the X/Y coordinates bubbletea
+// uses for mouse events (Col = X, Row = Y).
+type Region struct {
+	Row    int
+	Col    int
+	Width  int
+	Height int
+}
+
+// Contains reports whether the cell at (row, col) lies inside the region.
+func (r Region) Contains(row, col int) bool {
+	return row >= r.Row && row < r.Row+r.Height &&
+		col >= r.Col && col < r.Col+r.Width
+}
+
+// ContainsSpan reports whether the whole span lies inside the region.
+func (r Region) ContainsSpan(s Span) bool {
+	return s.Row >= r.Row && s.Row < r.Row+r.Height &&
+		s.Col >= r.Col && s.Col+s.Width <= r.Col+r.Width
+}
+
+// String formats the region as "row,col WxH".
+func (r Region) String() string {
+	return fmt.Sprintf("%d,%d %dx%d", r.Row, r.Col, r.Width, r.Height)
+}
+
+// Span is a single occurrence of text in the ANSI-stripped view.
+// Col and Width are measured in terminal cells, so wide runes (CJK, emoji)
+// count as two columns, exactly as the terminal renders them.
+type Span struct {
+	Row   int
+	Col   int
+	Width int
+}
+
+// Center returns the (x, y) cell in the middle of the span, suitable for
+// building a mouse event that lands on the matched text.
+func (s Span) Center() (x, y int) {
+	return s.Col + s.Width/2, s.Row
+}
+
+// String formats the span as "row:col-end" (end exclusive).
+func (s Span) String() string {
+	return fmt.Sprintf("%d:%d-%d", s.Row, s.Col, s.Col+s.Width)
+}
+
+// Locate returns every span where text appears in model.View() after ANSI
+// stripping, in reading order (top to bottom, left to right). Matches on the
+// same line do not overlap. Text must not contain newlines.
+func Locate(model tea.Model, text string) []Span {
+	return LocateStr(model.View(), text)
+}
+
+// LocateStr is the string-based variant of Locate.
+func LocateStr(view string, text string) []Span {
+	if text == "" || strings.Contains(text, "\n") {
+		return nil
+	}
+	width := ansi.StringWidth(text)
+	var spans []Span
+	for row, line := range strings.Split(StripANSI(view), "\n") {
+		offset := 0
+		for {
+			idx := strings.Index(line[offset:], text)
+			if idx < 0 {
+				break
+			}
+			start := offset + idx
+			spans = append(spans, Span{
+				Row:   row,
+				Col:   ansi.StringWidth(line[:start]),
+				Width: width,
+			})
+			offset = start + len(text)
+		}
+	}
+	return spans
+}
+
+// LocateNth returns the n-th (zero-based) span where text appears in
+// model.View(). The second result is false if there are fewer than n+1 matches.
+func LocateNth(model tea.Model, text string, n int) (Span, bool) {
+	spans := Locate(model, text)
+	if n < 0 || n >= len(spans) {
+		return Span{}, false
+	}
+	return spans[n], true
+}
+
+// LocateIn returns the spans where text appears in model.View() that lie
+// entirely inside region r.
+func LocateIn(model tea.Model, text string, r Region) []Span {
+	var out []Span
+	for _, s := range Locate(model, text) {
+		if r.ContainsSpan(s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ClickText builds a left-button MouseClick at the centre of the single place
+// where text appears in model.View(). Fails the test immediately if the text
+// is not found or appears more than once; use ClickTextNth to pick one of
+// several matches explicitly.
+func ClickText(t testing.TB, model tea.Model, text string) tea.MouseMsg {
+	t.Helper()
+	spans := Locate(model, text)
+	switch len(spans) {
+	case 0:
+		fatalView(t, model.View(), "ClickText: %q not found in view\n  stripped view: %q", text, StripANSI(model.View()))
+		return tea.MouseMsg{}
+	case 1:
+	default:
+		fatalView(t, model.View(), "ClickText: %q is ambiguous, found %d matches at %s; use ClickTextNth", text, len(spans), formatSpans(spans))
+		return tea.MouseMsg{}
+	}
+	x, y := spans[0].Center()
+	return MouseClick(x, y)
+}
+
+// ClickTextNth builds a left-button MouseClick at the centre of the n-th
+// (zero-based) place where text appears in model.View(). Fails the test
+// immediately if there are fewer than n+1 matches.
+func ClickTextNth(t testing.TB, model tea.Model, text string, n int) tea.MouseMsg {
+	t.Helper()
+	spans := Locate(model, text)
+	if n < 0 || n >= len(spans) {
+		fatalView(t, model.View(), "ClickTextNth: want match %d of %q, but view has %d match(es)", n, text, len(spans))
+		return tea.MouseMsg{}
+	}
+	x, y := spans[n].Center()
+	return MouseClick(x, y)
+}
+
+// formatSpans renders spans as a comma-separated list for error messages.
+func formatSpans(spans []Span) string {
+	parts := make([]string, len(spans))
+	for i, s := range spans {
+		parts[i] = s.String()
+	}
+	return strings.Join(parts, ", ")
+}
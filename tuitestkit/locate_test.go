@@ -0,0 +1,167 @@
+package tuitestkit
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runFatal runs fn with a detached testing.T on its own goroutine so that
+// Fatalf (runtime.Goexit) does not abort the calling test. Returns the
+// detached T for inspection.
+func runFatal(fn func(t *testing.T)) *testing.T {
+	fake := &testing.T{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(fake)
+	}()
+	<-done
+	return fake
+}
+
+const locateView = "File  Edit  View\n" +
+	"\x1b[1m[ Save ]\x1b[0m  [ Cancel ]\n" +
+	"日本 Save"
+
+// --- Locate tests ---
+
+func TestLocateStr_SingleMatch(t *testing.T) {
+	spans := LocateStr(locateView, "Edit")
+	if len(spans) != 1 {
+		t.Fatalf("LocateStr: got %d spans, want 1", len(spans))
+	}
+	want := Span{Row: 0, Col: 6, Width: 4}
+	if spans[0] != want {
+		t.Errorf("LocateStr = %+v, want %+v", spans[0], want)
+	}
+}
+
+func TestLocateStr_IgnoresANSI(t *testing.T) {
+	spans := LocateStr(locateView, "Cancel")
+	if len(spans) != 1 {
+		t.Fatalf("LocateStr: got %d spans, want 1", len(spans))
+	}
+	want := Span{Row: 1, Col: 12, Width: 6}
+	if spans[0] != want {
+		t.Errorf("LocateStr = %+v, want %+v", spans[0], want)
+	}
+}
+
+func TestLocateStr_WideRunesCountAsTwoCells(t *testing.T) {
+	spans := LocateStr(locateView, "Save")
+	if len(spans) != 2 {
+		t.Fatalf("LocateStr: got %d spans, want 2", len(spans))
+	}
+	if spans[0] != (Span{Row: 1, Col: 2, Width: 4}) {
+		t.Errorf("first match = %+v", spans[0])
+	}
+	// "日本 " occupies 5 cells.
+	if spans[1] != (Span{Row: 2, Col: 5, Width: 4}) {
+		t.Errorf("second match = %+v", spans[1])
+	}
+}
+
+func TestLocateStr_NonOverlapping(t *testing.T) {
+	spans := LocateStr("aaaa", "aa")
+	if len(spans) != 2 {
+		t.Fatalf("LocateStr: got %d spans, want 2", len(spans))
+	}
+	if spans[1].Col != 2 {
+		t.Errorf("second match col = %d, want 2", spans[1].Col)
+	}
+}
+
+func TestLocateStr_NoMatch(t *testing.T) {
+	if spans := LocateStr(locateView, "Quit"); len(spans) != 0 {
+		t.Errorf("LocateStr: got %v, want no spans", spans)
+	}
+	if spans := LocateStr(locateView, ""); len(spans) != 0 {
+		t.Errorf("LocateStr empty text: got %v, want no spans", spans)
+	}
+}
+
+func TestLocateNth(t *testing.T) {
+	m := styledModel{content: locateView}
+	s, ok := LocateNth(m, "Save", 1)
+	if !ok || s.Row != 2 {
+		t.Errorf("LocateNth(1) = %+v, %v; want row 2", s, ok)
+	}
+	if _, ok := LocateNth(m, "Save", 2); ok {
+		t.Error("LocateNth(2) should report no match")
+	}
+}
+
+func TestLocateIn(t *testing.T) {
+	m := styledModel{content: locateView}
+	spans := LocateIn(m, "Save", Region{Row: 2, Col: 0, Width: 20, Height: 1})
+	if len(spans) != 1 || spans[0].Row != 2 {
+		t.Errorf("LocateIn = %v, want the row-2 match only", spans)
+	}
+	// Region that cuts through the match excludes it.
+	if spans := LocateIn(m, "Save", Region{Row: 1, Col: 0, Width: 4, Height: 1}); len(spans) != 0 {
+		t.Errorf("LocateIn partial region = %v, want none", spans)
+	}
+}
+
+func TestSpan_Center(t *testing.T) {
+	x, y := Span{Row: 3, Col: 10, Width: 4}.Center()
+	if x != 12 || y != 3 {
+		t.Errorf("Center = (%d, %d), want (12, 3)", x, y)
+	}
+}
+
+// --- ClickText tests ---
+
+func TestClickText_Unique(t *testing.T) {
+	m := styledModel{content: locateView}
+	msg := ClickText(t, m, "Cancel")
+	if msg.X != 15 || msg.Y != 1 {
+		t.Errorf("ClickText = (%d, %d), want (15, 1)", msg.X, msg.Y)
+	}
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		t.Errorf("ClickText should build a left press, got %+v", msg)
+	}
+}
+
+func TestClickText_AmbiguousFails(t *testing.T) {
+	m := styledModel{content: locateView}
+	fake := runFatal(func(t *testing.T) { ClickText(t, m, "Save") })
+	if !fake.Failed() {
+		t.Error("ClickText should fail when the text matches more than once")
+	}
+}
+
+func TestClickText_MissingFails(t *testing.T) {
+	m := styledModel{content: locateView}
+	fake := runFatal(func(t *testing.T) { ClickText(t, m, "Quit") })
+	if !fake.Failed() {
+		t.Error("ClickText should fail when the text is not found")
+	}
+}
+
+func TestClickText_FatalfReturns(t *testing.T) {
+	m := styledModel{content: locateView}
+	for name, click := range map[string]func(tb testing.TB) tea.MouseMsg{
+		"missing":   func(tb testing.TB) tea.MouseMsg { return ClickText(tb, m, "Quit") },
+		"ambiguous": func(tb testing.TB) tea.MouseMsg { return ClickText(tb, m, "Save") },
+		"nth":       func(tb testing.TB) tea.MouseMsg { return ClickTextNth(tb, m, "Save", 5) },
+	} {
+		tb := &mockTB{}
+		if msg := click(tb); !tb.failed || msg != (tea.MouseMsg{}) {
+			t.Errorf("%s: failed = %v, msg = %+v; want a failure and no click", name, tb.failed, msg)
+		}
+	}
+}
+
+func TestClickTextNth(t *testing.T) {
+	m := styledModel{content: locateView}
+	msg := ClickTextNth(t, m, "Save", 1)
+	if msg.X != 7 || msg.Y != 2 {
+		t.Errorf("ClickTextNth = (%d, %d), want (7, 2)", msg.X, msg.Y)
+	}
+	fake := runFatal(func(t *testing.T) { ClickTextNth(t, m, "Save", 5) })
+	if !fake.Failed() {
+		t.Error("ClickTextNth should fail when the index is out of range")
+	}
+}
@@ -0,0 +1,148 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// defaultMaskFill is the character masked cells are replaced with.
+const defaultMaskFill = '#'
+
+// snapshotMask is a screen area excluded from a snapshot. desc is recorded in
+// the golden file header; resolve finds the area in the view being
+// snapshotted.
+type snapshotMask struct {
+	desc    string
+	resolve func(view string) ([]Region, error)
+}
+
+// MaskRegion replaces a rectangular area, given in cells (Row/Col 0-based),
+// with the mask fill character before comparison. Use it for volatile parts
+// of a fixed layout such as a clock in the status bar:
+//
+//	kit.SnapshotView(t, m, "board", kit.MaskRegion(kit.Region{Row: 23, Col: 72, Width: 8, Height: 1}))
+//
+// Parts of the region outside the view are ignored; lines shorter than the
+// region are padded so the mask is always fully visible.
+func MaskRegion(r Region) SnapshotOption {
+	return addMask(snapshotMask{
+		desc:    "region " + r.String(),
+		resolve: func(string) ([]Region, error) { return []Region{r}, nil },
+	})
+}
+
+// MaskPane masks the content area (inside the border) of the pane found by
+// FindPane(title). The snapshot fails if no such pane is rendered.
+func MaskPane(title string) SnapshotOption {
+	return addMask(snapshotMask{
+		desc: fmt.Sprintf("pane %q", title),
+		resolve: func(view string) ([]Region, error) {
+			box, ok := findPaneStr(view, title)
+			if !ok {
+				return nil, fmt.Errorf("mask pane %q: pane not found", title)
+			}
+			return []Region{box.Inner()}, nil
+		},
+	})
+}
+
+// MaskAfter masks width cells directly after every occurrence of label, for
+// values next to a stable caption ("Elapsed: 3m12s", "Updated 12:04"). The
+// snapshot fails if label is not in the view.
+func MaskAfter(label string, width int) SnapshotOption {
+	return addMask(snapshotMask{
+		desc: fmt.Sprintf("after %q %d", label, width),
+		resolve: func(view string) ([]Region, error) {
+			spans := LocateStr(view, label)
+			if len(spans) == 0 {
+				return nil, fmt.Errorf("mask after %q: text not found", label)
+			}
+			regions := make([]Region, len(spans))
+			for i, s := range spans {
+				regions[i] = Region{Row: s.Row, Col: s.Col + s.Width, Width: width, Height: 1}
+			}
+			return regions, nil
+		},
+	})
+}
+
+// MaskFill sets the character masked cells are replaced with (default '#').
+// It must be a single-cell character.
+func MaskFill(fill rune) SnapshotOption {
+	return func(c *snapshotConfig) {
+		c.maskFill = fill
+	}
+}
+
+func addMask(m snapshotMask) SnapshotOption {
+	return func(c *snapshotConfig) {
+		c.masks = append(c.masks, m)
+	}
+}
+
+// applyMasks resolves every mask against view and fills the masked cells.
+// All masks are resolved before any is applied, so one mask cannot hide the
+// label another one looks for.
+func (c snapshotConfig) applyMasks(view string) (string, error) {
+	if len(c.masks) == 0 {
+		return view, nil
+	}
+	var regions []Region
+	for _, m := range c.masks {
+		r, err := m.resolve(view)
+		if err != nil {
+			return "", err
+		}
+		regions = append(regions, r...)
+	}
+
+	fill := string(c.fill())
+	lines := strings.Split(view, "\n")
+	for _, r := range regions {
+		for row := max(r.Row, 0); row < r.Row+r.Height && row < len(lines); row++ {
+			lines[row] = maskLine(lines[row], max(r.Col, 0), r.Col+r.Width, fill)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// fill returns the configured mask fill character.
+func (c snapshotConfig) fill() rune {
+	if c.maskFill == 0 {
+		return defaultMaskFill
+	}
+	return c.maskFill
+}
+
+// maskLine replaces cells [col0, col1) of line with fill, keeping ANSI
+// sequences outside the mask. Lines shorter than col1 are padded with spaces
+// up to the mask; a wide character straddling either edge is masked whole.
+func maskLine(line string, col0, col1 int, fill string) string {
+	if col1 <= col0 {
+		return line
+	}
+	width := ansi.StringWidth(line)
+
+	left := ansi.Truncate(line, col0, "")
+	if lw := ansi.StringWidth(left); lw < col0 {
+		pad := " "
+		if lw < width {
+			pad = fill // a wide character was cut off
+		}
+		left += strings.Repeat(pad, col0-lw)
+	}
+	if width <= col1 {
+		return left + strings.Repeat(fill, col1-col0)
+	}
+
+	right := ansi.TruncateLeft(line, col1, "")
+	extra := 0
+	for ansi.StringWidth(right) > width-col1-extra {
+		// A wide character starts inside the mask: mask it whole.
+		extra++
+		right = ansi.TruncateLeft(line, col1+extra, "")
+	}
+	return left + strings.Repeat(fill, col1-col0+extra) + right
+}
@@ -0,0 +1,132 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaskLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		col0, col1 int
+		want       string
+	}{
+		{"middle", "status 12:04 ok", 7, 12, "status ##### ok"},
+		{"end", "clock 12:04", 6, 11, "clock #####"},
+		{"short line padded", "ab", 4, 6, "ab  ##"},
+		{"wide char at left edge", "a世bc", 2, 4, "a###c"},
+		{"wide char at right edge", "ab世c", 1, 3, "a###c"},
+		{"empty mask", "abc", 2, 2, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskLine(tt.line, tt.col0, tt.col1, "#"); got != tt.want {
+				t.Errorf("maskLine(%q, %d, %d) = %q, want %q", tt.line, tt.col0, tt.col1, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskLine_KeepsANSIOutsideMask(t *testing.T) {
+	line := "\x1b[1mtime\x1b[0m 12:04 \x1b[32mok\x1b[0m"
+	got := maskLine(line, 5, 10, "#")
+	if StripANSI(got) != "time ##### ok" {
+		t.Errorf("stripped = %q", StripANSI(got))
+	}
+	if !strings.Contains(got, "\x1b[1m") || !strings.Contains(got, "\x1b[32m") {
+		t.Errorf("styles outside the mask should survive: %q", got)
+	}
+}
+
+func TestApplyMasks(t *testing.T) {
+	view := strings.Join([]string{
+		"╭─Clock──╮ Elapsed: 3m12s",
+		"│ 12:04  │ Elapsed: 1h",
+		"╰────────╯",
+	}, "\n")
+	cfg := newSnapshotConfig([]SnapshotOption{MaskPane("Clock"), MaskAfter("Elapsed: ", 6), MaskFill('░')})
+
+	got, err := cfg.applyMasks(view)
+	if err != nil {
+		t.Fatalf("applyMasks: %v", err)
+	}
+	want := strings.Join([]string{
+		"╭─Clock──╮ Elapsed: ░░░░░░",
+		"│░░░░░░░░│ Elapsed: ░░░░░░",
+		"╰────────╯",
+	}, "\n")
+	if got != want {
+		t.Errorf("applyMasks =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestApplyMasks_RegionOutsideView(t *testing.T) {
+	cfg := newSnapshotConfig([]SnapshotOption{MaskRegion(Region{Row: 1, Col: 0, Width: 2, Height: 5})})
+	got, err := cfg.applyMasks("abc\ndef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "abc\n##f" {
+		t.Errorf("applyMasks = %q", got)
+	}
+}
+
+func TestApplyMasks_NotFound(t *testing.T) {
+	for _, opt := range []SnapshotOption{MaskPane("Missing"), MaskAfter("nope", 3)} {
+		cfg := newSnapshotConfig([]SnapshotOption{opt})
+		if _, err := cfg.applyMasks("plain view"); err == nil {
+			t.Errorf("%s: expected an error", cfg.masks[0].desc)
+		}
+	}
+}
+
+func TestSnapshot_MaskRecordedInHeader(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	mask := MaskRegion(Region{Row: 0, Col: 6, Width: 5, Height: 1})
+	SnapshotStr(t, "clock 12:04", "masked", mask)
+
+	data, err := os.ReadFile(filepath.Join(dir, "masked.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# tuitestkit snapshot\n# mask: region 0,6 5x1\n# mask-fill: #\n# ---\nclock #####"
+	if string(data) != want {
+		t.Errorf("golden =\n%s\nwant\n%s", data, want)
+	}
+
+	UpdateSnapshots = false
+	ft := &fakeT{}
+	snapshot(ft, "clock 09:59", "masked", 1, mask)
+	if ft.failed {
+		t.Errorf("masked content should match: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshot_MaskNotFoundFails(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	UpdateSnapshots = true
+
+	ft := &fakeT{}
+	runSnapshot(ft, "no panes here", "nomask", 1, MaskPane("Clock"))
+	if !ft.fataled || !strings.Contains(ft.lastErr, `pane "Clock"`) {
+		t.Errorf("expected fatal naming the pane, got: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshot_NoHeaderWithoutMetadata(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	SnapshotStr(t, "plain", "plain", WithScrubbers(ScrubUUIDs()))
+	data, _ := os.ReadFile(filepath.Join(dir, "plain.golden"))
+	if string(data) != "plain" {
+		t.Errorf("golden = %q, want no header", data)
+	}
+}
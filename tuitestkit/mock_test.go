@@ -245,9 +245,17 @@ func (m *mockTB) Errorf(format string, args ...any) {
 	m.failed = true
 	m.logs = append(m.logs, fmt.Sprintf(format, args...))
 }
+
+// Fatalf records the failure and returns, like a wrapper that does not stop
+// the test goroutine.
+func (m *mockTB) Fatalf(format string, args ...any) {
+	m.Errorf(format, args...)
+}
 func (m *mockTB) Log(args ...any) {
 	m.logs = append(m.logs, fmt.Sprint(args...))
 }
+func (m *mockTB) Name() string     { return "mockTB" }
+func (m *mockTB) Cleanup(f func()) {}
 
 func TestAssertCalled_Pass(t *testing.T) {
 	var r MockCallRecorder
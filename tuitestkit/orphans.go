@@ -0,0 +1,235 @@
+package tuitestkit
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// pruneSnapshotsFlag is registered as -prune-snapshots in test binaries.
+var pruneSnapshotsFlag *bool
+
+func init() {
+	if testing.Testing() {
+		pruneSnapshotsFlag = flag.Bool("prune-snapshots", false, "tuitestkit: delete golden files not used by any test (requires SnapshotMain)")
+	}
+}
+
+// snapshotTouched records every golden file (or archive entry, as
+// goldenRef.String) read or written during this run.
+var snapshotTouched = struct {
+	sync.Mutex
+	paths map[string]bool
+}{paths: map[string]bool{}}
+
+// touchSnapshot marks ref as used by the current test run.
+func touchSnapshot(ref goldenRef) {
+	if abs, err := filepath.Abs(ref.path); err == nil {
+		ref.path = abs
+	}
+	snapshotTouched.Lock()
+	defer snapshotTouched.Unlock()
+	snapshotTouched.paths[ref.String()] = true
+}
+
+// SnapshotMain runs the package tests and then looks for orphaned golden
+// files: *.golden files and txtar archive entries under testdata/snapshots/
+// (and any other directory a snapshot was read from or written to) that no
+// test touched, and writes the HTML report when enabled (see EnableReport).
+// It also warns about UpdateSnapshotsFilter patterns that selected no
+// snapshot. Call it from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//	    os.Exit(tuitestkit.SnapshotMain(m))
+//	}
+//
+// Orphans are reported on stderr. With FAIL_ORPHAN_SNAPSHOTS=1 the run fails
+// when any exist; with PRUNE_SNAPSHOTS=1 or -prune-snapshots they are deleted.
+//
+// The check only runs after a complete, passing run. It is skipped when tests
+// fail or when the run is filtered (-run, -skip, -short, -list), since tests
+// that did not run would leave their golden files looking orphaned. Tests that
+// call t.Skip before snapshotting have the same effect, so prefer the report
+// over pruning for packages with conditional skips.
+func SnapshotMain(m *testing.M) int {
+	report.Lock()
+	report.final = true
+	report.Unlock()
+	code := m.Run()
+	flushReport()
+	for _, p := range unmatchedUpdatePatterns() {
+		fmt.Fprintf(os.Stderr, "tuitestkit: update pattern %q matched no snapshot\n", p)
+	}
+	if code != 0 {
+		return code
+	}
+	if partialTestRun(flagValue) {
+		fmt.Fprintln(os.Stderr, "tuitestkit: filtered test run, skipping orphaned snapshot check")
+		return code
+	}
+
+	snapshotTouched.Lock()
+	touched := make(map[string]bool, len(snapshotTouched.paths))
+	for p := range snapshotTouched.paths {
+		touched[p] = true
+	}
+	snapshotTouched.Unlock()
+
+	orphans, err := findOrphanSnapshots(snapshotRoots(touched), touched)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuitestkit: orphaned snapshot check failed: %v\n", err)
+		return 1
+	}
+	prune := os.Getenv("PRUNE_SNAPSHOTS") == "1" || (pruneSnapshotsFlag != nil && *pruneSnapshotsFlag)
+	fail := os.Getenv("FAIL_ORPHAN_SNAPSHOTS") == "1"
+	return reportOrphanSnapshots(os.Stderr, orphans, prune, fail)
+}
+
+// flagValue returns the string value of a registered flag, or "".
+func flagValue(name string) string {
+	if f := flag.Lookup(name); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+// partialTestRun reports whether the test flags restrict which tests ran.
+func partialTestRun(lookup func(name string) string) bool {
+	for _, name := range []string{"test.run", "test.skip", "test.list"} {
+		if lookup(name) != "" {
+			return true
+		}
+	}
+	return lookup("test.short") == "true"
+}
+
+// snapshotRoots returns the directories to scan: the default snapshot
+// directory of the package under test, snapshotBaseDir when set, and the
+// directory of every touched golden file.
+func snapshotRoots(touched map[string]bool) []string {
+	set := map[string]bool{}
+	if abs, err := filepath.Abs(filepath.Join("testdata", "snapshots")); err == nil {
+		set[abs] = true
+	}
+	if snapshotBaseDir != "" {
+		if abs, err := filepath.Abs(snapshotBaseDir); err == nil {
+			set[abs] = true
+		}
+	}
+	for p := range touched {
+		set[filepath.Dir(parseGoldenRef(p).path)] = true
+	}
+	roots := make([]string, 0, len(set))
+	for r := range set {
+		roots = append(roots, r)
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// findOrphanSnapshots walks roots for *.golden files and *.txtar archive
+// entries not present in touched. Archive entries are reported as
+// goldenRef.String. Missing roots are ignored. The result is sorted and free
+// of duplicates.
+func findOrphanSnapshots(roots []string, touched map[string]bool) ([]string, error) {
+	found := map[string]bool{}
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && path == root {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if strings.HasSuffix(path, ".txtar") {
+				entries, err := loadArchive(path)
+				if err != nil {
+					return err
+				}
+				for key := range entries {
+					if ref := (goldenRef{path: path, key: key}).String(); !touched[ref] {
+						found[ref] = true
+					}
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".golden") {
+				return nil
+			}
+			if !touched[path] {
+				found[path] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	orphans := make([]string, 0, len(found))
+	for p := range found {
+		orphans = append(orphans, p)
+	}
+	sort.Strings(orphans)
+	return orphans, nil
+}
+
+// reportOrphanSnapshots prints orphans to w, deleting them when prune is set.
+// Returns the exit code: 1 if orphans remain and fail is set, 0 otherwise.
+func reportOrphanSnapshots(w io.Writer, orphans []string, prune, fail bool) int {
+	if len(orphans) == 0 {
+		return 0
+	}
+	if prune {
+		fmt.Fprintf(w, "tuitestkit: pruning %d orphaned golden file(s):\n", len(orphans))
+		code := 0
+		for _, p := range orphans {
+			if err := removeGolden(parseGoldenRef(p)); err != nil {
+				fmt.Fprintf(w, "  %s: %v\n", relPath(p), err)
+				code = 1
+				continue
+			}
+			fmt.Fprintf(w, "  %s\n", relPath(p))
+		}
+		return code
+	}
+
+	fmt.Fprintf(w, "tuitestkit: %d orphaned golden file(s) not used by any test:\n", len(orphans))
+	for _, p := range orphans {
+		fmt.Fprintf(w, "  %s\n", relPath(p))
+	}
+	fmt.Fprintln(w, "Run with PRUNE_SNAPSHOTS=1 (or -prune-snapshots) to delete them.")
+	if fail {
+		return 1
+	}
+	return 0
+}
+
+// removeGolden deletes a golden file or archive entry.
+func removeGolden(ref goldenRef) error {
+	if ref.key != "" {
+		return deleteArchiveEntry(ref.path, ref.key)
+	}
+	return os.Remove(ref.path)
+}
+
+// relPath returns path relative to the working directory when possible.
+func relPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,118 @@
+package tuitestkit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeGolden creates a golden file (and its directory) under dir.
+func writeGolden(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name+".golden")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindOrphanSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	used := writeGolden(t, dir, "used")
+	old := writeGolden(t, dir, "old")
+	nested := writeGolden(t, dir, "TestGone/sub-1")
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	orphans, err := findOrphanSnapshots([]string{dir, filepath.Join(dir, "missing")}, map[string]bool{used: true})
+	if err != nil {
+		t.Fatalf("findOrphanSnapshots: %v", err)
+	}
+	want := []string{nested, old}
+	if !reflect.DeepEqual(orphans, want) {
+		t.Errorf("orphans = %v, want %v", orphans, want)
+	}
+}
+
+func TestSnapshotFile_TouchesPath(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	SnapshotStr(t, "content", "touched")
+
+	snapshotTouched.Lock()
+	ok := snapshotTouched.paths[filepath.Join(dir, "touched.golden")]
+	snapshotTouched.Unlock()
+	if !ok {
+		t.Error("snapshot should record the golden path as touched")
+	}
+}
+
+func TestReportOrphanSnapshots_Report(t *testing.T) {
+	dir := t.TempDir()
+	old := writeGolden(t, dir, "old")
+
+	var out bytes.Buffer
+	if code := reportOrphanSnapshots(&out, []string{old}, false, false); code != 0 {
+		t.Errorf("report mode exit code = %d, want 0", code)
+	}
+	if !strings.Contains(out.String(), "1 orphaned golden file") || !strings.Contains(out.String(), "PRUNE_SNAPSHOTS=1") {
+		t.Errorf("unexpected report:\n%s", out.String())
+	}
+	if _, err := os.Stat(old); err != nil {
+		t.Error("report mode must not delete files")
+	}
+}
+
+func TestReportOrphanSnapshots_Fail(t *testing.T) {
+	var out bytes.Buffer
+	if code := reportOrphanSnapshots(&out, []string{"/nowhere/old.golden"}, false, true); code != 1 {
+		t.Errorf("fail mode exit code = %d, want 1", code)
+	}
+	if code := reportOrphanSnapshots(&out, nil, false, true); code != 0 {
+		t.Errorf("fail mode without orphans exit code = %d, want 0", code)
+	}
+}
+
+func TestReportOrphanSnapshots_Prune(t *testing.T) {
+	dir := t.TempDir()
+	old := writeGolden(t, dir, "old")
+
+	var out bytes.Buffer
+	if code := reportOrphanSnapshots(&out, []string{old}, true, true); code != 0 {
+		t.Errorf("prune exit code = %d, want 0", code)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("prune mode should delete orphaned files")
+	}
+}
+
+func TestPartialTestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  bool
+	}{
+		{"full run", map[string]string{"test.short": "false"}, false},
+		{"-run", map[string]string{"test.run": "TestBoard"}, true},
+		{"-skip", map[string]string{"test.skip": "TestSlow"}, true},
+		{"-list", map[string]string{"test.list": "."}, true},
+		{"-short", map[string]string{"test.short": "true"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := partialTestRun(func(name string) string { return tt.flags[name] })
+			if got != tt.want {
+				t.Errorf("partialTestRun = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package tuitestkit
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Box-drawing connection bits: which neighbours a border rune joins.
+const (
+	connUp uint8 = 1 << iota
+	connDown
+	connLeft
+	connRight
+)
+
+// boxRunes maps the box-drawing runes used by lipgloss NormalBorder,
+// RoundedBorder, ThickBorder and DoubleBorder to the directions they connect.
+var boxRunes = map[rune]uint8{
+	// Normal / rounded
+	'─': connLeft | connRight,
+	'│': connUp | connDown,
+	'┌': connDown | connRight,
+	'┐': connDown | connLeft,
+	'└': connUp | connRight,
+	'┘': connUp | connLeft,
+	'╭': connDown | connRight,
+	'╮': connDown | connLeft,
+	'╰': connUp | connRight,
+	'╯': connUp | connLeft,
+	'├': connUp | connDown | connRight,
+	'┤': connUp | connDown | connLeft,
+	'┬': connDown | connLeft | connRight,
+	'┴': connUp | connLeft | connRight,
+	'┼': connUp | connDown | connLeft | connRight,
+
+	// Thick
+	'━': connLeft | connRight,
+	'┃': connUp | connDown,
+	'┏': connDown | connRight,
+	'┓': connDown | connLeft,
+	'┗': connUp | connRight,
+	'┛': connUp | connLeft,
+	'┣': connUp | connDown | connRight,
+	'┫': connUp | connDown | connLeft,
+	'┳': connDown | connLeft | connRight,
+	'┻': connUp | connLeft | connRight,
+	'╋': connUp | connDown | connLeft | connRight,
+
+	// Double
+	'═': connLeft | connRight,
+	'║': connUp | connDown,
+	'╔': connDown | connRight,
+	'╗': connDown | connLeft,
+	'╚': connUp | connRight,
+	'╝': connUp | connLeft,
+	'╠': connUp | connDown | connRight,
+	'╣': connUp | connDown | connLeft,
+	'╦': connDown | connLeft | connRight,
+	'╩': connUp | connLeft | connRight,
+	'╬': connUp | connDown | connLeft | connRight,
+}
+
+// connects reports whether cell (row, col) is a box-drawing rune joining
+// every direction in mask.
+func (g cellGrid) connects(row, col int, mask uint8) bool {
+	return boxRunes[g.runeAt(row, col)]&mask == mask
+}
+
+// isBoxRune reports whether cell (row, col) holds any box-drawing rune.
+func (g cellGrid) isBoxRune(row, col int) bool {
+	_, ok := boxRunes[g.runeAt(row, col)]
+	return ok
+}
+
+// Box is a bordered rectangle detected in a rendered view.
+type Box struct {
+	// Bounds covers the whole box, border included.
+	Bounds Region
+	// Title is the text embedded in the top border (e.g. "╭─ Details ─╮"),
+	// or "" when the top border is plain.
+	Title string
+	// Lines holds the content inside the border, one entry per inner row,
+	// with trailing spaces removed.
+	Lines []string
+}
+
+// Inner returns the region inside the border.
+func (b Box) Inner() Region {
+	return Region{
+		Row:    b.Bounds.Row + 1,
+		Col:    b.Bounds.Col + 1,
+		Width:  b.Bounds.Width - 2,
+		Height: b.Bounds.Height - 2,
+	}
+}
+
+// Content returns the inner lines joined with newlines.
+func (b Box) Content() string {
+	return strings.Join(b.Lines, "\n")
+}
+
+// Contains reports whether text appears on any inner line of the box.
+func (b Box) Contains(text string) bool {
+	for _, line := range b.Lines {
+		if strings.Contains(line, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// heading returns the box title, or its first non-blank content line when
+// the top border carries no title.
+func (b Box) heading() string {
+	if b.Title != "" {
+		return b.Title
+	}
+	for _, line := range b.Lines {
+		if s := strings.TrimSpace(line); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// Boxes detects the bordered boxes in model.View(). See BoxesStr.
+func Boxes(model tea.Model) []Box {
+	return BoxesStr(model.View())
+}
+
+// BoxesStr detects the rectangles drawn with box-drawing runes in view and
+// returns them in reading order of their top-left corner.
+//
+// Each box is the smallest closed rectangle starting at its top-left corner,
+// so adjacent panes sharing a border (joined by ┬ ┴ ├ ┤ ┼) come back as
+// separate boxes, and nested boxes are reported alongside their parent.
+// Text inside the top border becomes the box title.
+func BoxesStr(view string) []Box {
+	g := newCellGrid(view)
+	var boxes []Box
+	for row := 0; row < g.height(); row++ {
+		for col := range g.rows[row] {
+			if !g.connects(row, col, connDown|connRight) {
+				continue
+			}
+			if b, ok := g.boxAt(row, col); ok {
+				boxes = append(boxes, b)
+			}
+		}
+	}
+	return boxes
+}
+
+// boxAt finds the narrowest box whose top-left corner is at (top, left).
+func (g cellGrid) boxAt(top, left int) (Box, bool) {
+	for right := left + 1; right < len(g.rows[top]); right++ {
+		if g.connects(top, right, connDown|connLeft) {
+			if bottom, ok := g.closeBox(top, left, right); ok {
+				return g.newBox(top, left, bottom, right), true
+			}
+		}
+		// The top edge continues through horizontal runes and title text;
+		// any other border rune ends it.
+		if g.isBoxRune(top, right) && !g.connects(top, right, connLeft|connRight) {
+			break
+		}
+	}
+	return Box{}, false
+}
+
+// closeBox walks down the left and right edges of a candidate box and returns
+// the row of the first bottom edge that closes it.
+func (g cellGrid) closeBox(top, left, right int) (int, bool) {
+	for row := top + 1; row < g.height(); row++ {
+		if g.connects(row, left, connUp|connRight) && g.connects(row, right, connUp|connLeft) && g.horizontalEdge(row, left, right) {
+			return row, true
+		}
+		if !g.connects(row, left, connUp|connDown) || !g.connects(row, right, connUp|connDown) {
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// horizontalEdge reports whether every cell strictly between left and right
+// on row is a horizontal border rune.
+func (g cellGrid) horizontalEdge(row, left, right int) bool {
+	for col := left + 1; col < right; col++ {
+		if !g.connects(row, col, connLeft|connRight) {
+			return false
+		}
+	}
+	return true
+}
+
+// newBox builds a Box from its corner coordinates.
+func (g cellGrid) newBox(top, left, bottom, right int) Box {
+	b := Box{
+		Bounds: Region{Row: top, Col: left, Width: right - left + 1, Height: bottom - top + 1},
+	}
+	var title strings.Builder
+	for col := left + 1; col < right; col++ {
+		if g.isBoxRune(top, col) {
+			title.WriteByte(' ')
+		} else {
+			title.WriteString(g.at(top, col))
+		}
+	}
+	b.Title = strings.Join(strings.Fields(title.String()), " ")
+	for row := top + 1; row < bottom; row++ {
+		b.Lines = append(b.Lines, strings.TrimRight(g.text(row, left+1, right), " "))
+	}
+	return b
+}
+
+// FindPane returns the first box in model.View() whose title equals title.
+// Boxes without a border title are matched by their first non-blank line,
+// which covers the common lipgloss pattern of a bold header inside the pane.
+func FindPane(model tea.Model, title string) (Box, bool) {
+	return findPaneStr(model.View(), title)
+}
+
+// findPaneStr is the string-based implementation of FindPane.
+func findPaneStr(view string, title string) (Box, bool) {
+	for _, b := range BoxesStr(view) {
+		if b.heading() == title {
+			return b, true
+		}
+	}
+	return Box{}, false
+}
+
+// PaneAssert scopes view assertions to a single detected pane.
+// Obtain one with Pane.
+type PaneAssert struct {
+	t    testing.TB
+	view string // the whole view, for the report
+	box  Box
+}
+
+// Pane locates the pane titled title in model.View() (see FindPane) and
+// returns assertions scoped to its content. Fails the test immediately if
+// no such pane exists.
+//
+// Example:
+//
+//	tuitestkit.Pane(t, m, "Details").Contains("TASK-3")
+func Pane(t testing.TB, model tea.Model, title string) *PaneAssert {
+	t.Helper()
+	view := model.View()
+	b, ok := findPaneStr(view, title)
+	if !ok {
+		var found []string
+		for _, b := range BoxesStr(view) {
+			found = append(found, b.heading())
+		}
+		fatalView(t, view, "Pane: no pane titled %q (found %d pane(s): %q)", title, len(found), found)
+		return &PaneAssert{t: t, view: view}
+	}
+	return &PaneAssert{t: t, view: view, box: b}
+}
+
+// Box returns the detected pane.
+func (p *PaneAssert) Box() Box {
+	return p.box
+}
+
+// Contains asserts that the pane content contains text.
+func (p *PaneAssert) Contains(text string) *PaneAssert {
+	p.t.Helper()
+	if !p.box.Contains(text) {
+		failView(p.t, p.view, "Pane %q: content does not contain %q\n  pane content: %q", p.box.heading(), text, p.box.Content())
+	}
+	return p
+}
+
+// NotContains asserts that the pane content does NOT contain text.
+func (p *PaneAssert) NotContains(text string) *PaneAssert {
+	p.t.Helper()
+	if p.box.Contains(text) {
+		failView(p.t, p.view, "Pane %q: content unexpectedly contains %q\n  pane content: %q", p.box.heading(), text, p.box.Content())
+	}
+	return p
+}
+
+// HasSize asserts the outer size of the pane, border included.
+func (p *PaneAssert) HasSize(width, height int) *PaneAssert {
+	p.t.Helper()
+	if p.box.Bounds.Width != width || p.box.Bounds.Height != height {
+		failView(p.t, p.view, "Pane %q: size = %dx%d, want %dx%d", p.box.heading(), p.box.Bounds.Width, p.box.Bounds.Height, width, height)
+	}
+	return p
+}
+
+// AssertPaneCount asserts that model.View() contains exactly n boxes.
+func AssertPaneCount(t testing.TB, model tea.Model, n int) {
+	t.Helper()
+	view := model.View()
+	if got := len(BoxesStr(view)); got != n {
+		failView(t, view, "AssertPaneCount: view has %d pane(s), want %d", got, n)
+	}
+}
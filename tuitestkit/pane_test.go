@@ -0,0 +1,131 @@
+package tuitestkit
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sharedBorderView is a two-column layout whose panes share the middle
+// border, with a full-width status pane underneath.
+const sharedBorderView = "" +
+	"┌─ Tasks ──┬─ Details ───┐\n" +
+	"│ TASK-1   │ TASK-3      │\n" +
+	"│ TASK-3   │ Status: Done│\n" +
+	"├──────────┴─────────────┤\n" +
+	"│ 2 tasks                │\n" +
+	"└────────────────────────┘"
+
+// --- BoxesStr tests ---
+
+func TestBoxesStr_LipglossRounded(t *testing.T) {
+	view := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Render("hello\nworld")
+	boxes := BoxesStr(view)
+	if len(boxes) != 1 {
+		t.Fatalf("BoxesStr: got %d boxes, want 1", len(boxes))
+	}
+	b := boxes[0]
+	if b.Bounds != (Region{Row: 0, Col: 0, Width: 7, Height: 4}) {
+		t.Errorf("Bounds = %+v", b.Bounds)
+	}
+	if b.Title != "" {
+		t.Errorf("Title = %q, want empty", b.Title)
+	}
+	if b.Content() != "hello\nworld" {
+		t.Errorf("Content = %q", b.Content())
+	}
+}
+
+func TestBoxesStr_SideBySide(t *testing.T) {
+	style := lipgloss.NewStyle().Border(lipgloss.NormalBorder())
+	view := lipgloss.JoinHorizontal(lipgloss.Top, style.Render("left"), " ", style.Render("right"))
+	boxes := BoxesStr(view)
+	if len(boxes) != 2 {
+		t.Fatalf("BoxesStr: got %d boxes, want 2", len(boxes))
+	}
+	if boxes[1].Bounds.Col != 7 || !boxes[1].Contains("right") {
+		t.Errorf("second box = %+v", boxes[1])
+	}
+}
+
+func TestBoxesStr_SharedBorders(t *testing.T) {
+	boxes := BoxesStr(sharedBorderView)
+	if len(boxes) != 3 {
+		t.Fatalf("BoxesStr: got %d boxes, want 3: %+v", len(boxes), boxes)
+	}
+	want := []struct {
+		title  string
+		bounds Region
+	}{
+		{"Tasks", Region{Row: 0, Col: 0, Width: 12, Height: 4}},
+		{"Details", Region{Row: 0, Col: 11, Width: 15, Height: 4}},
+		{"", Region{Row: 3, Col: 0, Width: 26, Height: 3}},
+	}
+	for i, w := range want {
+		if boxes[i].Title != w.title || boxes[i].Bounds != w.bounds {
+			t.Errorf("box %d = %q %+v, want %q %+v", i, boxes[i].Title, boxes[i].Bounds, w.title, w.bounds)
+		}
+	}
+}
+
+func TestBoxesStr_Nested(t *testing.T) {
+	inner := lipgloss.NewStyle().Border(lipgloss.NormalBorder()).Render("inner")
+	outer := lipgloss.NewStyle().Border(lipgloss.DoubleBorder()).Render("outer\n" + inner)
+	boxes := BoxesStr(outer)
+	if len(boxes) != 2 {
+		t.Fatalf("BoxesStr: got %d boxes, want 2", len(boxes))
+	}
+	if !boxes[0].Contains("inner") {
+		t.Error("outer box should contain the nested box content")
+	}
+	if boxes[1].Bounds != (Region{Row: 2, Col: 1, Width: 7, Height: 3}) {
+		t.Errorf("inner Bounds = %+v", boxes[1].Bounds)
+	}
+}
+
+func TestBoxesStr_NoBoxes(t *testing.T) {
+	if boxes := BoxesStr("plain text\n──────\n│ not closed"); len(boxes) != 0 {
+		t.Errorf("BoxesStr: got %+v, want none", boxes)
+	}
+}
+
+// --- Pane tests ---
+
+func TestPane_ContainsScopedToPane(t *testing.T) {
+	m := styledModel{content: sharedBorderView}
+	Pane(t, m, "Details").Contains("Status: Done").NotContains("TASK-1")
+	Pane(t, m, "Tasks").Contains("TASK-1").HasSize(12, 4)
+}
+
+func TestPane_MatchesFirstLineWhenUntitled(t *testing.T) {
+	m := styledModel{content: sharedBorderView}
+	Pane(t, m, "2 tasks").HasSize(26, 3)
+}
+
+func TestPane_ContainsFail(t *testing.T) {
+	fake := &testing.T{}
+	m := styledModel{content: sharedBorderView}
+	Pane(fake, m, "Details").Contains("TASK-1")
+	if !fake.Failed() {
+		t.Error("Pane.Contains should fail for text outside the pane")
+	}
+}
+
+func TestPane_MissingFails(t *testing.T) {
+	m := styledModel{content: sharedBorderView}
+	fake := runFatal(func(t *testing.T) { Pane(t, m, "Logs") })
+	if !fake.Failed() {
+		t.Error("Pane should fail when no pane has the title")
+	}
+}
+
+func TestAssertPaneCount(t *testing.T) {
+	m := styledModel{content: sharedBorderView}
+	AssertPaneCount(t, m, 3)
+
+	fake := &testing.T{}
+	AssertPaneCount(fake, m, 2)
+	if !fake.Failed() {
+		t.Error("AssertPaneCount should fail on wrong count")
+	}
+}
@@ -0,0 +1,155 @@
+package tuitestkit
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// pendingSuffix is appended to a golden file path to store the actual output
+// of a failed comparison until it is reviewed.
+const pendingSuffix = ".new"
+
+// writePendingSnapshot stores content next to the golden file as
+// <name>.golden.new so the mismatch can be reviewed and accepted later
+// without re-running the tests. Archived snapshots go into the same entry of
+// a sibling <archive>.txtar.new. Errors are returned for the caller to report;
+// a failed pending write never hides the snapshot failure itself.
+func writePendingSnapshot(ref goldenRef, content string) error {
+	if ref.key != "" {
+		return setArchiveEntry(ref.path+pendingSuffix, ref.key, content)
+	}
+	return goldenRef{path: ref.path + pendingSuffix}.write(content)
+}
+
+// clearPendingSnapshot removes a stale pending snapshot once the snapshot
+// matches or has been rewritten. Best effort: there is usually nothing to remove.
+func clearPendingSnapshot(ref goldenRef) {
+	if ref.key != "" {
+		if _, err := os.Stat(ref.path + pendingSuffix); err == nil {
+			_ = deleteArchiveEntry(ref.path+pendingSuffix, ref.key)
+		}
+		return
+	}
+	_ = os.Remove(ref.path + pendingSuffix)
+}
+
+// PendingSnapshot is a snapshot mismatch waiting for review: the actual
+// output stored as <name>.golden.new next to the golden file it would replace,
+// or as an entry of <archive>.txtar.new for archived snapshots.
+type PendingSnapshot struct {
+	// Name is the golden path relative to the search root, without the
+	// .golden.new suffix (e.g. "testdata/snapshots/board-list"). For archived
+	// snapshots it is the archive path followed by "#" and the entry name
+	// (e.g. "testdata/snapshots/snapshots.txtar#board-list").
+	Name string
+	// GoldenPath is the golden file (or archive) the pending output would
+	// replace.
+	GoldenPath string
+	// PendingPath is the .golden.new file (or .txtar.new archive) holding the
+	// actual output.
+	PendingPath string
+	// Key is the archive entry name; "" for .golden files.
+	Key string
+}
+
+// IsNew reports whether there is no golden file yet, i.e. accepting the
+// pending output creates the snapshot.
+func (p PendingSnapshot) IsNew() bool {
+	_, err := goldenRef{path: p.GoldenPath, key: p.Key}.read()
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// Expected returns the current golden content, or "" for a new snapshot.
+func (p PendingSnapshot) Expected() (string, error) {
+	data, err := goldenRef{path: p.GoldenPath, key: p.Key}.read()
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return data, err
+}
+
+// Actual returns the pending output recorded by the failed comparison.
+func (p PendingSnapshot) Actual() (string, error) {
+	return goldenRef{path: p.PendingPath, key: p.Key}.read()
+}
+
+// Accept replaces the golden file with the pending output.
+func (p PendingSnapshot) Accept() error {
+	if p.Key == "" {
+		return os.Rename(p.PendingPath, p.GoldenPath)
+	}
+	content, err := p.Actual()
+	if err != nil {
+		return err
+	}
+	if err := setArchiveEntry(p.GoldenPath, p.Key, content); err != nil {
+		return err
+	}
+	return deleteArchiveEntry(p.PendingPath, p.Key)
+}
+
+// Reject discards the pending output and keeps the golden file.
+func (p PendingSnapshot) Reject() error {
+	if p.Key == "" {
+		return os.Remove(p.PendingPath)
+	}
+	return deleteArchiveEntry(p.PendingPath, p.Key)
+}
+
+// FindPendingSnapshots walks root for *.golden.new files and the entries of
+// *.txtar.new archives and returns them sorted by name. A missing root yields
+// no results.
+func FindPendingSnapshots(root string) ([]PendingSnapshot, error) {
+	var pending []PendingSnapshot
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(path, ".txtar"+pendingSuffix) {
+			entries, err := loadArchive(path)
+			if err != nil {
+				return err
+			}
+			archive := strings.TrimSuffix(path, pendingSuffix)
+			rel, err := filepath.Rel(root, archive)
+			if err != nil {
+				rel = archive
+			}
+			for key := range entries {
+				pending = append(pending, PendingSnapshot{
+					Name:        filepath.ToSlash(rel) + "#" + key,
+					GoldenPath:  archive,
+					PendingPath: path,
+					Key:         key,
+				})
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".golden"+pendingSuffix) {
+			return nil
+		}
+		golden := strings.TrimSuffix(path, pendingSuffix)
+		name, err := filepath.Rel(root, strings.TrimSuffix(golden, ".golden"))
+		if err != nil {
+			name = golden
+		}
+		pending = append(pending, PendingSnapshot{
+			Name:        filepath.ToSlash(name),
+			GoldenPath:  golden,
+			PendingPath: path,
+		})
+		return nil
+	})
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Name < pending[j].Name })
+	return pending, err
+}
@@ -0,0 +1,193 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile creates path with content, failing the test on error.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// --- pending file lifecycle ---
+
+func TestSnapshot_MismatchWritesPending(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	golden := filepath.Join(dir, "pend.golden")
+	writeFile(t, golden, "old")
+
+	ft := &fakeT{}
+	snapshot(ft, "new", "pend", 1)
+
+	data, err := os.ReadFile(golden + ".new")
+	if err != nil {
+		t.Fatalf("pending file not written: %v", err)
+	}
+	if string(data) != "new" {
+		t.Errorf("pending content = %q, want %q", data, "new")
+	}
+	if !strings.Contains(ft.lastErr, "pend.golden.new") || !strings.Contains(ft.lastErr, "snapreview") {
+		t.Errorf("error should point at the pending file and snapreview, got: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshot_MissingGoldenWritesPending(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+
+	ft := &fakeT{}
+	runSnapshot(ft, "fresh", "brand-new", 1)
+
+	if !ft.fataled {
+		t.Fatal("expected fatal on missing golden file")
+	}
+	if !fileExists(filepath.Join(dir, "brand-new.golden.new")) {
+		t.Error("missing golden should leave a pending file for review")
+	}
+}
+
+func TestSnapshot_MatchClearsPending(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	golden := filepath.Join(dir, "stale.golden")
+	writeFile(t, golden, "same")
+	writeFile(t, golden+".new", "outdated")
+
+	ft := &fakeT{}
+	snapshot(ft, "same", "stale", 1)
+
+	if ft.failed {
+		t.Fatalf("unexpected failure: %s", ft.lastErr)
+	}
+	if fileExists(golden + ".new") {
+		t.Error("matching snapshot should remove the stale pending file")
+	}
+}
+
+func TestSnapshot_UpdateClearsPending(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+	golden := filepath.Join(dir, "upd.golden")
+	writeFile(t, golden+".new", "outdated")
+
+	SnapshotStr(t, "written", "upd")
+
+	if fileExists(golden + ".new") {
+		t.Error("updating a snapshot should remove its pending file")
+	}
+}
+
+// --- PendingSnapshot ---
+
+func TestFindPendingSnapshots(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "b", "testdata", "snapshots", "list.golden"), "old")
+	writeFile(t, filepath.Join(root, "b", "testdata", "snapshots", "list.golden.new"), "new")
+	writeFile(t, filepath.Join(root, "a", "testdata", "snapshots", "fresh.golden.new"), "fresh")
+	writeFile(t, filepath.Join(root, "a", "testdata", "snapshots", "ok.golden"), "ok")
+
+	got, err := FindPendingSnapshots(root)
+	if err != nil {
+		t.Fatalf("FindPendingSnapshots: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d pending snapshots, want 2: %v", len(got), got)
+	}
+	if got[0].Name != "a/testdata/snapshots/fresh" || got[1].Name != "b/testdata/snapshots/list" {
+		t.Errorf("names = %q, %q; want sorted relative names", got[0].Name, got[1].Name)
+	}
+	if !got[0].IsNew() || got[1].IsNew() {
+		t.Errorf("IsNew = %v, %v; want true, false", got[0].IsNew(), got[1].IsNew())
+	}
+
+	exp, err := got[0].Expected()
+	if err != nil || exp != "" {
+		t.Errorf("Expected of new snapshot = %q, %v; want empty", exp, err)
+	}
+	exp, _ = got[1].Expected()
+	act, _ := got[1].Actual()
+	if exp != "old" || act != "new" {
+		t.Errorf("Expected/Actual = %q/%q, want old/new", exp, act)
+	}
+}
+
+func TestFindPendingSnapshots_MissingRoot(t *testing.T) {
+	got, err := FindPendingSnapshots(filepath.Join(t.TempDir(), "nope"))
+	if err != nil || len(got) != 0 {
+		t.Errorf("missing root: got %v, %v; want nothing", got, err)
+	}
+}
+
+func TestPendingSnapshot_AcceptAndReject(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "acc.golden"), "old")
+	writeFile(t, filepath.Join(dir, "acc.golden.new"), "new")
+	writeFile(t, filepath.Join(dir, "rej.golden"), "old")
+	writeFile(t, filepath.Join(dir, "rej.golden.new"), "new")
+
+	pending, err := FindPendingSnapshots(dir)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("FindPendingSnapshots = %v, %v", pending, err)
+	}
+	if err := pending[0].Accept(); err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if err := pending[1].Reject(); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir, "acc.golden")); string(data) != "new" {
+		t.Errorf("accepted golden = %q, want %q", data, "new")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "rej.golden")); string(data) != "old" {
+		t.Errorf("rejected golden = %q, want %q", data, "old")
+	}
+	if left, _ := FindPendingSnapshots(dir); len(left) != 0 {
+		t.Errorf("pending files left after review: %v", left)
+	}
+}
+
+// --- DiffLines ---
+
+func TestDiffLines(t *testing.T) {
+	got := DiffLines("a\nb\nc", "a\nx\nc\nd")
+	want := []DiffLine{
+		{Op: DiffEqual, Text: "a", OldLine: 1, NewLine: 1},
+		{Op: DiffDelete, Text: "b", OldLine: 2},
+		{Op: DiffInsert, Text: "x", NewLine: 2},
+		{Op: DiffEqual, Text: "c", OldLine: 3, NewLine: 3},
+		{Op: DiffInsert, Text: "d", NewLine: 4},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DiffLines: got %d lines, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDiffLines_Identical(t *testing.T) {
+	for _, l := range DiffLines("x\ny", "x\ny") {
+		if l.Op != DiffEqual {
+			t.Errorf("identical input produced %+v", l)
+		}
+	}
+}
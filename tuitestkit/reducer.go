@@ -2,6 +2,8 @@ package tuitestkit
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -24,12 +26,20 @@ type Step[S any, A any] struct {
 
 // ReducerSequence defines a multi-step test scenario for a reducer.
 // Actions are applied sequentially starting from Initial.
-// Final is the required assertion on the end state.
+// Final is the required assertion on the end state, unless Golden is set.
+//
+// Golden, when set, records a transcript of the sequence (the initial state,
+// then each step's action and resulting state, see FormatState) and compares
+// it with the golden file of that name, approval-test style. Step and Final
+// assertions still run when given, but are then optional: an intended
+// behaviour change is reviewed as a transcript diff and re-recorded with
+// UPDATE_SNAPSHOTS=1 instead of editing assertions.
 type ReducerSequence[S, A any] struct {
 	Name    string
 	Initial S
 	Steps   []Step[S, A]
 	Final   func(t *testing.T, got S)
+	Golden  string
 }
 
 // Invariant defines a property that must hold for any state produced by the reducer.
@@ -78,16 +88,22 @@ func RunReducerTests[S, A any](t *testing.T, reduce func(S, A) S, tests []Reduce
 func RunReducerSequences[S, A any](t *testing.T, reduce func(S, A) S, sequences []ReducerSequence[S, A]) {
 	t.Helper()
 	for _, seq := range sequences {
+		var ref goldenRef
+		if seq.Golden != "" {
+			ref = defaultSnapshotter.ref(seq.Golden, 2)
+		}
 		t.Run(seq.Name, func(t *testing.T) {
 			t.Helper()
 			state := seq.Initial
+			frames := []storyFrame{{label: "initial", view: transcriptState(state)}}
 			for i, step := range seq.Steps {
 				state = reduce(state, step.Action)
+				name := step.Name
+				if name == "" {
+					name = fmt.Sprintf("step-%d", i)
+				}
+				frames = append(frames, storyFrame{label: name, view: transcriptAction(step.Action) + "\n" + transcriptState(state)})
 				if step.Assert != nil {
-					name := step.Name
-					if name == "" {
-						name = fmt.Sprintf("step-%d", i)
-					}
 					t.Run(name, func(t *testing.T) {
 						t.Helper()
 						step.Assert(t, state)
@@ -97,10 +113,60 @@ func RunReducerSequences[S, A any](t *testing.T, reduce func(S, A) S, sequences
 			if seq.Final != nil {
 				seq.Final(t, state)
 			}
+			if seq.Golden != "" {
+				defaultSnapshotter.snapshotFile(t, joinFrames(frames), seq.Golden, ref, withTranscript(frames, nil)...)
+			}
 		})
 	}
 }
 
+// Reducer transcripts use the storyboard file format with one frame per
+// step, holding FormatState lines rooted at "action" and "state":
+//
+//	=== frame 1: initial ===
+//	state.Count = 0
+//	=== frame 2: increment ===
+//	action.(cart.addItem).ID = "a1"
+//	state.Count = 1
+//
+// A mismatch names the first diverging step and its changed paths.
+
+// withTranscript prepends the options marking a transcript of FormatState
+// frames.
+func withTranscript(frames []storyFrame, opts []SnapshotOption) []SnapshotOption {
+	return append([]SnapshotOption{withFrames(frames), func(c *snapshotConfig) { c.state = true }}, opts...)
+}
+
+// transcriptState formats a state for a transcript frame.
+func transcriptState(state any) string {
+	return rootState("state", FormatState(state))
+}
+
+// transcriptAction formats an action, with its dynamic type, for a
+// transcript frame.
+func transcriptAction(action any) string {
+	if action == nil {
+		return "action = nil"
+	}
+	return rootState("action.("+reflect.TypeOf(action).String()+")", FormatState(action))
+}
+
+// rootState renames the root "." of FormatState lines to root.
+func rootState(root, formatted string) string {
+	lines := strings.Split(formatted, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, ". = "):
+			lines[i] = root + line[1:]
+		case strings.HasPrefix(line, ".["):
+			lines[i] = root + line[1:]
+		default:
+			lines[i] = root + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // WrapWithInvariants wraps a reducer function with invariant checking.
 // After every reduce call, all invariants are checked. If any invariant
 // is violated, t.Fatalf is called with the violation details.
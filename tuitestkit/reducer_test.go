@@ -2,6 +2,9 @@ package tuitestkit
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -235,6 +238,68 @@ func TestRunReducerSequences_EmptySteps(t *testing.T) {
 	RunReducerSequences(t, counterReduce, seq)
 }
 
+func TestRunReducerSequences_GoldenTranscript(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	CreateMissingSnapshots = true
+	seq := []ReducerSequence[counterState, counterAction]{
+		{
+			Name:    "transcript",
+			Initial: counterState{Count: 0, Min: 0, Max: 3},
+			Steps: []Step[counterState, counterAction]{
+				{Name: "inc", Action: actionIncrement},
+				{Action: actionDouble},
+			},
+			Golden: "counter-transcript",
+		},
+	}
+
+	RunReducerSequences(t, counterReduce, seq)
+
+	data, err := os.ReadFile(filepath.Join(dir, "counter-transcript.golden"))
+	if err != nil {
+		t.Fatalf("transcript not written: %v", err)
+	}
+	want := strings.Join([]string{
+		"=== frame 1: initial ===",
+		"state.Count = 0",
+		"state.Min = 0",
+		"state.Max = 3",
+		"=== frame 2: inc ===",
+		"action.(tuitestkit.counterAction) = 0",
+		"state.Count = 1",
+		"state.Min = 0",
+		"state.Max = 3",
+		"=== frame 3: step-1 ===",
+		"action.(tuitestkit.counterAction) = 3",
+		"state.Count = 2",
+		"state.Min = 0",
+		"state.Max = 3",
+	}, "\n")
+	if string(data) != want {
+		t.Errorf("transcript =\n%s\nwant\n%s", data, want)
+	}
+
+	// Same sequence again compares against the recorded transcript.
+	RunReducerSequences(t, counterReduce, seq)
+}
+
+func TestReducerTranscriptDiff(t *testing.T) {
+	exp := "=== frame 1: initial ===\nstate.Count = 0\n=== frame 2: inc ===\naction = 0\nstate.Count = 1"
+	act := "=== frame 1: initial ===\nstate.Count = 0\n=== frame 2: inc ===\naction = 0\nstate.Count = 2"
+	diff := newSnapshotConfig(withTranscript(nil, nil)).diff(exp, act)
+	if !strings.Contains(diff, "first diverging frame: 2 (inc)") || !strings.Contains(diff, "~ state.Count: 1 → 2") {
+		t.Errorf("transcript diff should name the step and the changed path:\n%s", diff)
+	}
+}
+
+func TestRootState(t *testing.T) {
+	got := rootState("state", ". = 1\n.[0] = 2\n.x.y = 3")
+	if got != "state = 1\nstate[0] = 2\nstate.x.y = 3" {
+		t.Errorf("rootState = %q", got)
+	}
+}
+
 func TestRunReducerSequences_NilFinal(t *testing.T) {
 	// Should not panic when Final is nil
 	seq := []ReducerSequence[counterState, counterAction]{
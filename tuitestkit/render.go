@@ -0,0 +1,138 @@
+package tuitestkit
+
+import (
+	"io"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ColorProfile is the color capability a view is rendered for.
+type ColorProfile int
+
+const (
+	// ProfileAscii renders no colors at all, as with NO_COLOR or a dumb terminal.
+	ProfileAscii ColorProfile = iota
+	// ProfileANSI renders the 16 basic ANSI colors.
+	ProfileANSI
+	// ProfileANSI256 renders the 256-color xterm palette.
+	ProfileANSI256
+	// ProfileTrueColor renders 24-bit colors.
+	ProfileTrueColor
+)
+
+// String returns the lowercase profile name used in snapshot names.
+func (p ColorProfile) String() string {
+	switch p {
+	case ProfileAscii:
+		return "ascii"
+	case ProfileANSI:
+		return "ansi"
+	case ProfileANSI256:
+		return "ansi256"
+	case ProfileTrueColor:
+		return "truecolor"
+	}
+	return "unknown"
+}
+
+// termenv maps the profile to the termenv profile lipgloss uses internally.
+func (p ColorProfile) termenv() termenv.Profile {
+	switch p {
+	case ProfileANSI:
+		return termenv.ANSI
+	case ProfileANSI256:
+		return termenv.ANSI256
+	case ProfileTrueColor:
+		return termenv.TrueColor
+	}
+	return termenv.Ascii
+}
+
+// Background is the terminal background lipgloss adapts colors to
+// (lipgloss.AdaptiveColor picks Dark or Light based on it).
+type Background int
+
+const (
+	// BackgroundDark is a dark terminal background.
+	BackgroundDark Background = iota
+	// BackgroundLight is a light terminal background.
+	BackgroundLight
+)
+
+// String returns "dark" or "light".
+func (b Background) String() string {
+	if b == BackgroundLight {
+		return "light"
+	}
+	return "dark"
+}
+
+// RenderEnv pins the terminal environment a view is rendered for.
+// The zero value is Ascii on a dark background.
+type RenderEnv struct {
+	Profile    ColorProfile
+	Background Background
+}
+
+// String returns "<profile>-<background>", e.g. "truecolor-light".
+func (e RenderEnv) String() string {
+	return e.Profile.String() + "-" + e.Background.String()
+}
+
+// RenderMatrix returns every profile/background combination, from Ascii to
+// TrueColor, dark before light.
+func RenderMatrix() []RenderEnv {
+	var envs []RenderEnv
+	for _, p := range []ColorProfile{ProfileAscii, ProfileANSI, ProfileANSI256, ProfileTrueColor} {
+		for _, b := range []Background{BackgroundDark, BackgroundLight} {
+			envs = append(envs, RenderEnv{Profile: p, Background: b})
+		}
+	}
+	return envs
+}
+
+// NewRenderer returns a lipgloss renderer pinned to env. Nothing is detected
+// from the environment (TERM, NO_COLOR, COLORFGBG) and the global default
+// renderer is left untouched, so tests using it can run in parallel.
+//
+// Views must build their styles from this renderer (r.NewStyle()) rather
+// than lipgloss.NewStyle() for the pin to take effect:
+//
+//	r := tuitestkit.NewRenderer(tuitestkit.RenderEnv{Profile: tuitestkit.ProfileANSI256})
+//	m := board.New(board.WithRenderer(r))
+func NewRenderer(env RenderEnv) *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(env.Profile.termenv())
+	r.SetHasDarkBackground(env.Background == BackgroundDark)
+	return r
+}
+
+// SnapshotMatrix renders a view once per environment and snapshots each
+// result (raw, with canonical ANSI codes) as "<name>-<env>", e.g.
+// "board-ansi256-dark". Each environment runs as a subtest named after the
+// environment and is recorded in the golden file header (WithRenderEnv).
+// When envs is nil, the full RenderMatrix() is used; opts apply to every
+// snapshot.
+//
+// Example:
+//
+//	tuitestkit.SnapshotMatrix(t, "board", func(r *lipgloss.Renderer) string {
+//	    return board.New(board.WithRenderer(r)).View()
+//	}, nil)
+func SnapshotMatrix(t *testing.T, name string, render func(r *lipgloss.Renderer) string, envs []RenderEnv, opts ...SnapshotOption) {
+	t.Helper()
+	if len(envs) == 0 {
+		envs = RenderMatrix()
+	}
+	for _, env := range envs {
+		envName := name + "-" + env.String()
+		ref := defaultSnapshotter.ref(envName, 2)
+		t.Run(env.String(), func(t *testing.T) {
+			t.Helper()
+			envOpts := append([]SnapshotOption{WithRenderEnv(env)}, opts...)
+			defaultSnapshotter.snapshotFile(t, render(NewRenderer(env)), envName, ref, withANSIMode(ansiRaw, envOpts)...)
+		})
+	}
+}
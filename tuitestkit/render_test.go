@@ -0,0 +1,139 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// adaptiveView renders text in a color that depends on the background.
+func adaptiveView(r *lipgloss.Renderer) string {
+	return r.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#000000", Dark: "#ffffff"}).
+		Render("status")
+}
+
+// --- RenderEnv tests ---
+
+func TestRenderEnv_String(t *testing.T) {
+	env := RenderEnv{Profile: ProfileTrueColor, Background: BackgroundLight}
+	if got := env.String(); got != "truecolor-light" {
+		t.Errorf("String = %q, want %q", got, "truecolor-light")
+	}
+	if got := (RenderEnv{}).String(); got != "ascii-dark" {
+		t.Errorf("zero value String = %q, want %q", got, "ascii-dark")
+	}
+}
+
+func TestRenderMatrix(t *testing.T) {
+	envs := RenderMatrix()
+	if len(envs) != 8 {
+		t.Fatalf("RenderMatrix: got %d envs, want 8", len(envs))
+	}
+	seen := map[string]bool{}
+	for _, e := range envs {
+		seen[e.String()] = true
+	}
+	if len(seen) != 8 {
+		t.Errorf("RenderMatrix should not repeat environments: %v", envs)
+	}
+}
+
+// --- NewRenderer tests ---
+
+func TestNewRenderer_Ascii(t *testing.T) {
+	got := adaptiveView(NewRenderer(RenderEnv{Profile: ProfileAscii}))
+	if got != "status" {
+		t.Errorf("Ascii render = %q, want plain %q", got, "status")
+	}
+}
+
+func TestNewRenderer_TrueColorBackgrounds(t *testing.T) {
+	dark := adaptiveView(NewRenderer(RenderEnv{Profile: ProfileTrueColor, Background: BackgroundDark}))
+	light := adaptiveView(NewRenderer(RenderEnv{Profile: ProfileTrueColor, Background: BackgroundLight}))
+	if !strings.Contains(dark, "38;2;255;255;255") {
+		t.Errorf("dark render should use the Dark color: %q", dark)
+	}
+	if !strings.Contains(light, "38;2;0;0;0") {
+		t.Errorf("light render should use the Light color: %q", light)
+	}
+}
+
+func TestNewRenderer_ANSI256(t *testing.T) {
+	got := adaptiveView(NewRenderer(RenderEnv{Profile: ProfileANSI256}))
+	if !strings.Contains(got, "38;5;") {
+		t.Errorf("ANSI256 render should use a palette color: %q", got)
+	}
+}
+
+func TestNewRenderer_LeavesDefaultRendererAlone(t *testing.T) {
+	before := lipgloss.DefaultRenderer().ColorProfile()
+	NewRenderer(RenderEnv{Profile: ProfileTrueColor})
+	if after := lipgloss.DefaultRenderer().ColorProfile(); after != before {
+		t.Errorf("default renderer profile changed from %v to %v", before, after)
+	}
+}
+
+// --- SnapshotMatrix tests ---
+
+func TestSnapshotMatrix_WritesOneGoldenPerEnv(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	envs := []RenderEnv{
+		{Profile: ProfileAscii, Background: BackgroundDark},
+		{Profile: ProfileTrueColor, Background: BackgroundLight},
+	}
+	SnapshotMatrix(t, "status", adaptiveView, envs)
+
+	ascii, err := os.ReadFile(filepath.Join(dir, "status-ascii-dark.golden"))
+	if err != nil {
+		t.Fatalf("ascii golden not written: %v", err)
+	}
+	if want := "# profile: ascii\n# background: dark\n# scrubbers: none\n# ---\nstatus"; !strings.HasSuffix(string(ascii), want) {
+		t.Errorf("ascii golden = %q, want the env in the header and %q", ascii, "status")
+	}
+	tc, err := os.ReadFile(filepath.Join(dir, "status-truecolor-light.golden"))
+	if err != nil {
+		t.Fatalf("truecolor golden not written: %v", err)
+	}
+	if !strings.Contains(string(tc), "\x1b[") {
+		t.Errorf("truecolor golden should keep ANSI codes: %q", tc)
+	}
+
+	// Compare mode passes against the files just written.
+	UpdateSnapshots = false
+	SnapshotMatrix(t, "status", adaptiveView, envs)
+}
+
+func TestSnapshotMatrix_CanonicalSGR(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	envs := []RenderEnv{
+		{Profile: ProfileANSI, Background: BackgroundDark},
+		{Profile: ProfileANSI256, Background: BackgroundDark},
+	}
+	SnapshotMatrix(t, "sgr", func(*lipgloss.Renderer) string {
+		return "\x1b[1m\x1b[31mwarn\x1b[0m"
+	}, envs, WithScrubbers(ScrubDurations()))
+
+	data, err := os.ReadFile(filepath.Join(dir, "sgr-ansi256-dark.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# scrubbers: dur\n") || !strings.HasSuffix(string(data), "\x1b[1;31mwarn\x1b[0m") {
+		t.Errorf("golden should hold canonical SGR and the options: %q", data)
+	}
+
+	// The same styles encoded differently still match.
+	UpdateSnapshots = false
+	SnapshotMatrix(t, "sgr", func(*lipgloss.Renderer) string {
+		return "\x1b[31;1mwarn\x1b[m"
+	}, envs, WithScrubbers(ScrubDurations()))
+}
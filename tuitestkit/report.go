@@ -0,0 +1,453 @@
+package tuitestkit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The HTML report collects every snapshot comparison, failed view assertion
+// and trace (storyboard frames, transcripts, ReportView) of a test run into
+// one self-contained file, so the screens of a failed CI run can be browsed
+// offline, with their colors, next to their diffs. Enable it with
+// SNAPSHOT_REPORT=<file.html> or EnableReport in TestMain. A path ending in
+// "/" (or naming an existing directory) gets one <package dir>.html per
+// package, so `go test ./...` does not overwrite one report per package.
+//
+// Passing comparisons are listed without their screens, so the report of a
+// large suite stays small; storyboards keep their frames as traces. The file
+// is rewritten when a test with a failure finishes, and otherwise at most once
+// every reportFlushInterval, so failures are on disk even when the run is cut
+// short; SnapshotMain writes the complete report at the end. Without
+// SnapshotMain the file is rewritten after every test instead.
+
+// report is the collector for this test binary.
+var report = struct {
+	sync.Mutex
+	path    string
+	entries []reportEntry
+	flushes map[string]bool // tests with a pending write: whether they failed
+	written time.Time       // last successful write
+	final   bool            // SnapshotMain writes the report after the run
+	warned  bool            // the missing SnapshotMain warning was printed
+}{flushes: map[string]bool{}}
+
+// reportFlushInterval is the minimum time between writes of the report for
+// tests that passed, when SnapshotMain writes the last interval's entries.
+const reportFlushInterval = time.Second
+
+func init() {
+	if p := os.Getenv("SNAPSHOT_REPORT"); p != "" {
+		EnableReport(p)
+	}
+}
+
+// EnableReport turns on the HTML report and sets where it is written. Call it
+// from TestMain before m.Run. An empty path turns the report off.
+func EnableReport(path string) {
+	report.Lock()
+	defer report.Unlock()
+	report.path = reportFile(path)
+}
+
+// reportFile resolves a report path, mapping a directory to a file named
+// after the package under test.
+func reportFile(path string) string {
+	if path == "" {
+		return ""
+	}
+	info, err := os.Stat(path)
+	if strings.HasSuffix(path, "/") || (err == nil && info.IsDir()) {
+		name := "report"
+		if wd, err := os.Getwd(); err == nil {
+			name = filepath.Base(wd)
+		}
+		return filepath.Join(path, name+".html")
+	}
+	return path
+}
+
+// reportEntry is one item of the report.
+type reportEntry struct {
+	Test     string
+	Kind     string // snapshot, assertion or trace
+	Name     string
+	Status   string // match, mismatch, missing, updated, created, failed or recorded
+	Golden   string
+	Message  string
+	Expected []reportScreen
+	Actual   []reportScreen
+}
+
+// Failed reports whether the entry is a failure.
+func (e reportEntry) Failed() bool {
+	switch e.Status {
+	case "mismatch", "missing", "failed":
+		return true
+	}
+	return false
+}
+
+// reportScreen is one rendered view, labelled when it is a storyboard frame.
+type reportScreen struct {
+	Label string
+	HTML  template.HTML
+}
+
+// reporting reports whether the report is enabled.
+func reporting() bool {
+	report.Lock()
+	defer report.Unlock()
+	return report.path != ""
+}
+
+// addReportEntry records e and arranges for the report to be written when
+// the test finishes (or at once for test doubles without Cleanup); see
+// flushReportAfter.
+func addReportEntry(t snapshotT, e reportEntry) {
+	report.Lock()
+	if report.path == "" {
+		report.Unlock()
+		return
+	}
+	e.Test = t.Name()
+	report.entries = append(report.entries, e)
+	c, canCleanup := t.(interface{ Cleanup(func()) })
+	failed, registered := report.flushes[e.Test]
+	if canCleanup {
+		report.flushes[e.Test] = failed || e.Failed()
+	}
+	report.Unlock()
+
+	switch {
+	case canCleanup && !registered:
+		name := e.Test
+		c.Cleanup(func() {
+			report.Lock()
+			failed := report.flushes[name]
+			delete(report.flushes, name)
+			report.Unlock()
+			flushReportAfter(failed)
+		})
+	case !canCleanup:
+		flushReportAfter(e.Failed())
+	}
+}
+
+// flushReportAfter writes the report after a test finished: at once when it
+// failed or SnapshotMain is not running, otherwise only if the last write is
+// reportFlushInterval old.
+func flushReportAfter(failed bool) {
+	report.Lock()
+	due := failed || !report.final || time.Since(report.written) >= reportFlushInterval
+	warn := !report.final && !report.warned && report.path != ""
+	report.warned = report.warned || warn
+	report.Unlock()
+	if warn {
+		fmt.Fprintln(os.Stderr, "tuitestkit: report enabled without SnapshotMain, rewriting it after every test; call tuitestkit.SnapshotMain from TestMain")
+	}
+	if due {
+		flushReport()
+	}
+}
+
+// flushReport writes the report, warning on stderr when it cannot.
+func flushReport() {
+	if err := writeReport(); err != nil {
+		fmt.Fprintf(os.Stderr, "tuitestkit: cannot write report: %v\n", err)
+	}
+}
+
+// writeReport renders all entries collected so far to the report file.
+func writeReport() error {
+	report.Lock()
+	path := report.path
+	entries := append([]reportEntry(nil), report.entries...)
+	report.Unlock()
+	if path == "" {
+		return nil
+	}
+	var b bytes.Buffer
+	if err := reportTemplate.Execute(&b, reportPage{Entries: entries}); err != nil {
+		return err
+	}
+	defer lockGolden(path)()
+	if err := writeFileAtomic(path, b.String()); err != nil {
+		return err
+	}
+	report.Lock()
+	report.written = time.Now()
+	report.Unlock()
+	return nil
+}
+
+// recordSnapshot adds a snapshot comparison to the report. expected and
+// actual are prepared content as compared; the actual view is shown from
+// cfg.source, masked and scrubbed, when the snapshot stripped its colors. A match is listed
+// without screens, except that a storyboard keeps its frames as a trace.
+func recordSnapshot(t snapshotT, name string, ref goldenRef, cfg snapshotConfig, status, expected, actual, message string) {
+	if !reporting() {
+		return
+	}
+	e := reportEntry{
+		Kind:    "snapshot",
+		Name:    name,
+		Status:  status,
+		Golden:  relPath(ref.String()),
+		Message: message,
+	}
+	if cfg.storyboard && !cfg.state {
+		e.Kind = "trace"
+	}
+	if status == "match" && e.Kind != "trace" {
+		addReportEntry(t, e)
+		return
+	}
+	e.Actual = reportScreens(cfg, cfg.ansi, actual)
+	if cfg.source != "" {
+		// Show the colors, with the same masks and scrubbers as the
+		// compared content.
+		if source, err := cfg.applyMasks(cfg.source); err == nil {
+			e.Actual = reportScreens(cfg, ansiRaw, cfg.scrub(source))
+		}
+	}
+	switch status {
+	case "missing", "created", "match":
+	default:
+		e.Expected = reportScreens(cfg, cfg.ansi, expected)
+	}
+	addReportEntry(t, e)
+}
+
+// recordAssertion adds a failed view assertion to the report.
+func recordAssertion(t snapshotT, view, message string) {
+	if !reporting() {
+		return
+	}
+	name, _, _ := strings.Cut(message, ":")
+	addReportEntry(t, reportEntry{
+		Kind:    "assertion",
+		Name:    name,
+		Status:  "failed",
+		Message: message,
+		Actual:  []reportScreen{{HTML: screenHTML(parseStyledScreen(view))}},
+	})
+}
+
+// ReportView adds a view to the HTML report as a trace, e.g. an
+// intermediate screen worth seeing when a later assertion fails. It does
+// nothing unless the report is enabled.
+func ReportView(t testing.TB, label string, view string) {
+	t.Helper()
+	if !reporting() {
+		return
+	}
+	addReportEntry(t, reportEntry{
+		Kind:   "trace",
+		Name:   label,
+		Status: "recorded",
+		Actual: []reportScreen{{HTML: screenHTML(parseStyledScreen(view))}},
+	})
+}
+
+// failView fails the test with a view assertion message and adds the view
+// to the report.
+func failView(t testing.TB, view string, format string, args ...any) {
+	t.Helper()
+	msg := fmt.Sprintf(format, args...)
+	t.Errorf("%s", msg)
+	recordAssertion(t, view, msg)
+}
+
+// fatalView is failView that stops the test. The view is recorded first,
+// since Fatalf does not return.
+func fatalView(t testing.TB, view string, format string, args ...any) {
+	t.Helper()
+	msg := fmt.Sprintf(format, args...)
+	recordAssertion(t, view, msg)
+	t.Fatalf("%s", msg)
+}
+
+// reportScreens renders snapshot content stored in the given ANSI mode,
+// frame by frame for storyboards. Image snapshots are shown as the images.
+func reportScreens(cfg snapshotConfig, mode, content string) []reportScreen {
+	render := func(view string) template.HTML {
+		switch mode {
+		case ansiStyled:
+			return screenHTML(parseStyled(view))
+		case ansiImage:
+			return imageHTML(view)
+		}
+		return screenHTML(parseStyledScreen(view))
+	}
+	if !cfg.storyboard {
+		return []reportScreen{{HTML: render(content)}}
+	}
+	frames := splitFrames(content)
+	screens := make([]reportScreen, len(frames))
+	for i, f := range frames {
+		screens[i] = reportScreen{
+			Label: fmt.Sprintf("frame %d: %s", i+1, f.label),
+			HTML:  render(f.view),
+		}
+	}
+	return screens
+}
+
+// imageHTML embeds an SVG image snapshot as a data URL, so the report stays
+// one file and the SVG cannot affect the page.
+func imageHTML(svg string) template.HTML {
+	return template.HTML(`<img alt="" src="data:image/svg+xml;base64,` + base64.StdEncoding.EncodeToString([]byte(svg)) + `">`)
+}
+
+// colorHex resolves a cellStyle color to "#rrggbb" with the given palette
+// for the basic colors; "" stays "".
+func colorHex(c string, basic []string) string {
+	for i, name := range basicColorNames {
+		if c == name {
+			return basic[i]
+		}
+	}
+	return c
+}
+
+// styleCSS returns the inline CSS for a cell style, with the basic colors of
+// ThemeDark.
+func styleCSS(st cellStyle) string {
+	palette := ThemeDark.Palette[:]
+	fg, bg := colorHex(st.fg, palette), colorHex(st.bg, palette)
+	if st.attrs&attrReverse != 0 {
+		if fg == "" {
+			fg = "var(--fg)"
+		}
+		if bg == "" {
+			bg = "var(--bg)"
+		}
+		fg, bg = bg, fg
+	}
+	var css []string
+	if fg != "" {
+		css = append(css, "color:"+fg)
+	}
+	if bg != "" {
+		css = append(css, "background:"+bg)
+	}
+	if st.attrs&attrBold != 0 {
+		css = append(css, "font-weight:bold")
+	}
+	if st.attrs&attrFaint != 0 {
+		css = append(css, "opacity:.6")
+	}
+	if st.attrs&attrItalic != 0 {
+		css = append(css, "font-style:italic")
+	}
+	var lines []string
+	if st.attrs&attrUnderline != 0 {
+		lines = append(lines, "underline")
+	}
+	if st.attrs&attrStrike != 0 {
+		lines = append(lines, "line-through")
+	}
+	if len(lines) > 0 {
+		css = append(css, "text-decoration:"+strings.Join(lines, " "))
+		if ul := colorHex(st.ul, palette); ul != "" {
+			css = append(css, "text-decoration-color:"+ul)
+		}
+	}
+	if st.attrs&attrHidden != 0 {
+		css = append(css, "visibility:hidden")
+	}
+	return strings.Join(css, ";")
+}
+
+// screenHTML renders a styled screen as HTML for a <pre> block, one span per
+// run of identically styled cells.
+func screenHTML(scr styledScreen) template.HTML {
+	var b strings.Builder
+	for row, cells := range scr.rows {
+		if row > 0 {
+			b.WriteByte('\n')
+		}
+		for col := 0; col < len(cells); {
+			end := col + 1
+			for end < len(cells) && cells[end].style == cells[col].style {
+				end++
+			}
+			var text strings.Builder
+			for _, c := range cells[col:end] {
+				text.WriteString(c.text)
+			}
+			escaped := template.HTMLEscapeString(text.String())
+			if st := cells[col].style; st.isPlain() {
+				b.WriteString(escaped)
+			} else {
+				fmt.Fprintf(&b, `<span style="%s">%s</span>`, styleCSS(st), escaped)
+			}
+			col = end
+		}
+	}
+	return template.HTML(b.String())
+}
+
+// reportPage is the data of the report template.
+type reportPage struct {
+	Entries []reportEntry
+}
+
+// Failures counts the failed entries.
+func (p reportPage) Failures() int {
+	n := 0
+	for _, e := range p.Entries {
+		if e.Failed() {
+			n++
+		}
+	}
+	return n
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>tuitestkit report</title>
+<style>
+:root { --fg: #d4d4d4; --bg: #1e1e1e; }
+body { font-family: system-ui, sans-serif; margin: 1.5em; color: #222; }
+summary { cursor: pointer; padding: .3em 0; }
+details { border-bottom: 1px solid #ddd; padding: .2em 0; }
+.status { display: inline-block; min-width: 6em; font-weight: bold; }
+.failed .status { color: #c00; }
+.passed .status { color: #080; }
+.kind, .golden { color: #777; font-size: .9em; }
+.sides { display: flex; gap: 1.5em; flex-wrap: wrap; align-items: flex-start; }
+.side h4 { margin: .6em 0 .3em; }
+pre { font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 13px; line-height: 1.25; margin: 0; }
+pre.screen { color: var(--fg); background: var(--bg); padding: .5em; white-space: pre; }
+pre.message { background: #f6f6f6; padding: .5em; white-space: pre-wrap; }
+.label { font-size: .85em; color: #555; margin: .4em 0 .1em; }
+</style>
+</head>
+<body>
+<h1>tuitestkit report</h1>
+<p>{{len .Entries}} entries, {{.Failures}} failed.</p>
+{{range .Entries}}
+<details class="{{if .Failed}}failed{{else}}passed{{end}}"{{if .Failed}} open{{end}}>
+<summary><span class="status">{{.Status}}</span> {{.Test}} — {{.Name}} <span class="kind">{{.Kind}}</span></summary>
+{{if .Golden}}<p class="golden">{{.Golden}}</p>{{end}}
+{{if .Message}}<pre class="message">{{.Message}}</pre>{{end}}
+<div class="sides">
+{{if .Expected}}<div class="side"><h4>Expected</h4>{{range .Expected}}{{if .Label}}<div class="label">{{.Label}}</div>{{end}}<pre class="screen">{{.HTML}}</pre>{{end}}</div>{{end}}
+{{if .Actual}}<div class="side"><h4>Actual</h4>{{range .Actual}}{{if .Label}}<div class="label">{{.Label}}</div>{{end}}<pre class="screen">{{.HTML}}</pre>{{end}}</div>{{end}}
+</div>
+</details>
+{{end}}
+</body>
+</html>
+`))
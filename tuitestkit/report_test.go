@@ -0,0 +1,250 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// withReport enables the report at a temporary path for the duration of the
+// test and returns the path. It behaves as under SnapshotMain.
+func withReport(t *testing.T) string {
+	t.Helper()
+	report.Lock()
+	orig := struct {
+		path          string
+		entries       []reportEntry
+		written       time.Time
+		final, warned bool
+	}{report.path, report.entries, report.written, report.final, report.warned}
+	report.entries, report.written, report.final = nil, time.Time{}, true
+	report.Unlock()
+	path := filepath.Join(t.TempDir(), "report.html")
+	EnableReport(path)
+	t.Cleanup(func() {
+		report.Lock()
+		report.path, report.entries, report.written = orig.path, orig.entries, orig.written
+		report.final, report.warned = orig.final, orig.warned
+		report.Unlock()
+	})
+	return path
+}
+
+func readReport(t *testing.T, path string) string {
+	t.Helper()
+	if err := writeReport(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestReport_SnapshotComparisons(t *testing.T) {
+	path := withReport(t)
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	writeFile(t, filepath.Join(dir, "same.golden"), "ok")
+	writeFile(t, filepath.Join(dir, "changed.golden"), "old <b>")
+
+	ft := &fakeT{name: "TestBoard"}
+	snapshot(ft, "ok", "same", 1)
+	snapshot(ft, "new <b>", "changed", 1, withStripped("\x1b[31mnew <b>\x1b[0m", nil)...)
+
+	html := readReport(t, path)
+	for _, want := range []string{
+		"2 entries, 1 failed.",
+		`<span class="status">match</span> TestBoard — same`,
+		`<span class="status">mismatch</span> TestBoard — changed`,
+		`<span style="color:#cd0000">new &lt;b&gt;</span>`, // colors from the unstripped view
+		"old &lt;b&gt;",
+		"--- expected",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+	if strings.Contains(html, "http://") || strings.Contains(html, "https://") {
+		t.Error("report must not reference external assets")
+	}
+}
+
+func TestReport_ActualIsScrubbedAndMasked(t *testing.T) {
+	path := withReport(t)
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	writeFile(t, filepath.Join(dir, "volatile.golden"), "old")
+
+	view := "\x1b[31mtook 250ms\x1b[0m pid 4242"
+	opts := []SnapshotOption{WithScrubbers(ScrubDurations()), MaskRegion(Region{Row: 0, Col: 15, Width: 4, Height: 1})}
+	snapshot(&fakeT{name: "TestBoard"}, ansi.Strip(view), "volatile", 1, withStripped(view, opts)...)
+
+	html := readReport(t, path)
+	if !strings.Contains(html, `<span style="color:#cd0000">took &lt;dur&gt;</span>`) {
+		t.Error("actual view should keep its colors and be scrubbed")
+	}
+	if strings.Contains(html, "250ms") || strings.Contains(html, "4242") {
+		t.Error("actual view should not show scrubbed or masked content")
+	}
+}
+
+func TestReport_MatchesAreSummaries(t *testing.T) {
+	withReport(t)
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	writeFile(t, filepath.Join(dir, "same.golden"), "ok")
+
+	snapshot(&fakeT{name: "TestBoard"}, "ok", "same", 1)
+	report.Lock()
+	e := report.entries[0]
+	report.Unlock()
+	if e.Status != "match" || e.Expected != nil || e.Actual != nil {
+		t.Errorf("a match should be recorded without screens: %+v", e)
+	}
+}
+
+func TestReport_ThrottlesPassingFlushes(t *testing.T) {
+	path := withReport(t)
+	report.Lock()
+	report.written = time.Now()
+	report.Unlock()
+
+	addReportEntry(&fakeT{name: "TestA"}, reportEntry{Kind: "trace", Name: "passing", Status: "recorded"})
+	if fileExists(path) {
+		t.Error("a passing entry right after a write should not rewrite the report")
+	}
+	recordAssertion(&fakeT{name: "TestB"}, "x", "ContainsStr: missing")
+	if !fileExists(path) {
+		t.Error("a failure should be written at once")
+	}
+}
+
+func TestReport_WritesEveryTestWithoutSnapshotMain(t *testing.T) {
+	path := withReport(t)
+	report.Lock()
+	report.final, report.warned = false, true
+	report.written = time.Now()
+	report.Unlock()
+
+	addReportEntry(&fakeT{name: "TestA"}, reportEntry{Kind: "trace", Name: "passing", Status: "recorded"})
+	if !fileExists(path) {
+		t.Error("without SnapshotMain nothing writes the last entries later, so every test should write")
+	}
+}
+
+func TestReport_FailedViewAssertion(t *testing.T) {
+	path := withReport(t)
+	tb := &mockTB{}
+	ContainsStr(tb, "\x1b[1mhello\x1b[0m", "bye")
+
+	html := readReport(t, path)
+	if !strings.Contains(html, `<span class="status">failed</span> mockTB — ContainsStr`) {
+		t.Error("failed assertion should be listed by its name")
+	}
+	if !strings.Contains(html, `<span style="font-weight:bold">hello</span>`) {
+		t.Error("failed assertion should render the styled view")
+	}
+}
+
+func TestReport_PaneAssertions(t *testing.T) {
+	path := withReport(t)
+	m := stubModel{view: "╭─ Details ─╮\n│ \x1b[1mTASK-3\x1b[0m    │\n╰───────────╯"}
+	tb := &mockTB{}
+	Pane(tb, m, "Details").Contains("TASK-9").HasSize(3, 3)
+	Pane(tb, m, "Missing")
+	AssertPaneCount(tb, m, 2)
+
+	html := readReport(t, path)
+	for _, want := range []string{
+		`mockTB — Pane &#34;Details&#34;`,
+		`content does not contain &#34;TASK-9&#34;`,
+		`size = 13x3, want 3x3`,
+		`no pane titled &#34;Missing&#34;`,
+		`mockTB — AssertPaneCount`,
+		`<span style="font-weight:bold">TASK-3</span>`, // the whole view, styled
+		"4 entries, 4 failed.",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
+
+func TestReport_InlineSnapshots(t *testing.T) {
+	path := withReport(t)
+	withSnapshotDir(t, t.TempDir())
+	ft := &fakeT{name: "TestInline"}
+	cfg := newSnapshotConfig(withStripped("\x1b[32mnew\x1b[0m", nil))
+	snapshotInline(ft, cfg, "new", "old", "/src/demo_test.go", 12)
+	snapshotInline(ft, snapshotConfig{}, "same", "\nsame", "/src/demo_test.go", 20)
+
+	html := readReport(t, path)
+	for _, want := range []string{
+		`<span class="status">mismatch</span> TestInline — inline`,
+		`<span class="status">match</span> TestInline — inline`,
+		"/src/demo_test.go:12",
+		`<span style="color:#00cd00">new</span>`,
+		"2 entries, 1 failed.",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
+
+func TestReport_StoryboardFrames(t *testing.T) {
+	path := withReport(t)
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	CreateMissingSnapshots = true
+
+	sb := NewStoryboard(counterModel{})
+	sb.Send("after inc", incMsg{})
+	sb.Snapshot(t, "flow")
+
+	html := readReport(t, path)
+	for _, want := range []string{"created", "frame 1: initial", "frame 2: after inc", "count: 1", "trace"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
+
+func TestReportView(t *testing.T) {
+	path := withReport(t)
+	ReportView(t, "after load", "\x1b[7msel\x1b[0m")
+
+	html := readReport(t, path)
+	if !strings.Contains(html, `<span style="color:var(--bg);background:var(--fg)">sel</span>`) {
+		t.Errorf("reverse video should swap the default colors:\n%s", html)
+	}
+}
+
+func TestReport_DisabledRecordsNothing(t *testing.T) {
+	withReport(t)
+	EnableReport("")
+	ReportView(t, "ignored", "x")
+	report.Lock()
+	n := len(report.entries)
+	report.Unlock()
+	if n != 0 {
+		t.Errorf("disabled report recorded %d entries", n)
+	}
+}
+
+func TestReportFile_Directory(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if got, want := reportFile(dir), filepath.Join(dir, filepath.Base(wd)+".html"); got != want {
+		t.Errorf("reportFile(dir) = %q, want %q", got, want)
+	}
+	if got := reportFile("out/r.html"); got != "out/r.html" {
+		t.Errorf("reportFile(file) = %q", got)
+	}
+}
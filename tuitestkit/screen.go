@@ -0,0 +1,384 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// styleAttr is a set of SGR text attributes.
+type styleAttr uint8
+
+const (
+	attrBold styleAttr = 1 << iota
+	attrFaint
+	attrItalic
+	attrUnderline
+	attrBlink
+	attrReverse
+	attrHidden
+	attrStrike
+)
+
+// attrNames lists attributes in the order they are written, with their SGR
+// "on" code.
+var attrNames = []struct {
+	attr styleAttr
+	name string
+	code int
+}{
+	{attrBold, "bold", 1},
+	{attrFaint, "faint", 2},
+	{attrItalic, "italic", 3},
+	{attrUnderline, "underline", 4},
+	{attrBlink, "blink", 5},
+	{attrReverse, "reverse", 7},
+	{attrHidden, "hidden", 8},
+	{attrStrike, "strike", 9},
+}
+
+// basicColorNames are the 16 ANSI colors by palette index.
+var basicColorNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright-black", "bright-red", "bright-green", "bright-yellow",
+	"bright-blue", "bright-magenta", "bright-cyan", "bright-white",
+}
+
+// cellStyle is the SGR state a cell is drawn with. Colors (foreground,
+// background, underline) are "" for the terminal default, a basic color name
+// ("red", "bright-blue") for the 16 ANSI colors, or "#rrggbb" for 256-palette
+// and 24-bit colors, so equivalent encodings compare equal.
+type cellStyle struct {
+	attrs styleAttr
+	fg    string
+	bg    string
+	ul    string
+}
+
+// isPlain reports whether the style is the terminal default.
+func (s cellStyle) isPlain() bool {
+	return s == cellStyle{}
+}
+
+// attrString returns the attribute names separated by spaces, or "plain".
+func (s cellStyle) attrString() string {
+	var names []string
+	for _, a := range attrNames {
+		if s.attrs&a.attr != 0 {
+			names = append(names, a.name)
+		}
+	}
+	if len(names) == 0 {
+		return "plain"
+	}
+	return strings.Join(names, " ")
+}
+
+// String returns the style as space-separated tokens, e.g.
+// "bold underline fg=#ff0000 bg=blue ul=red", or "plain".
+func (s cellStyle) String() string {
+	var tokens []string
+	if s.attrs != 0 {
+		tokens = append(tokens, s.attrString())
+	}
+	if s.fg != "" {
+		tokens = append(tokens, "fg="+s.fg)
+	}
+	if s.bg != "" {
+		tokens = append(tokens, "bg="+s.bg)
+	}
+	if s.ul != "" {
+		tokens = append(tokens, "ul="+s.ul)
+	}
+	if len(tokens) == 0 {
+		return "plain"
+	}
+	return strings.Join(tokens, " ")
+}
+
+// parseCellStyle is the inverse of cellStyle.String.
+func parseCellStyle(s string) (cellStyle, error) {
+	var st cellStyle
+	for _, tok := range strings.Fields(s) {
+		switch {
+		case tok == "plain":
+		case strings.HasPrefix(tok, "fg="):
+			st.fg = tok[3:]
+		case strings.HasPrefix(tok, "bg="):
+			st.bg = tok[3:]
+		case strings.HasPrefix(tok, "ul="):
+			st.ul = tok[3:]
+		default:
+			found := false
+			for _, a := range attrNames {
+				if a.name == tok {
+					st.attrs |= a.attr
+					found = true
+				}
+			}
+			if !found {
+				return cellStyle{}, fmt.Errorf("unknown style %q", tok)
+			}
+		}
+	}
+	return st, nil
+}
+
+// applySGR updates the style with the parameters of one SGR sequence
+// ("1;38;5;196" or "38:2::255:0:0"). Unknown codes are ignored.
+func (s *cellStyle) applySGR(params string) {
+	if params == "" {
+		*s = cellStyle{}
+		return
+	}
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		sub := strings.Split(fields[i], ":")
+		code := atoiOr(sub[0], 0)
+		switch {
+		case code == 0:
+			*s = cellStyle{}
+		case code == 4 && len(sub) > 1 && sub[1] == "0":
+			s.attrs &^= attrUnderline
+		case code == 21:
+			s.attrs |= attrUnderline
+		case code == 22:
+			s.attrs &^= attrBold | attrFaint
+		case code == 23:
+			s.attrs &^= attrItalic
+		case code == 24:
+			s.attrs &^= attrUnderline
+		case code == 25:
+			s.attrs &^= attrBlink
+		case code == 27:
+			s.attrs &^= attrReverse
+		case code == 28:
+			s.attrs &^= attrHidden
+		case code == 29:
+			s.attrs &^= attrStrike
+		case code >= 30 && code <= 37:
+			s.fg = basicColorNames[code-30]
+		case code == 39:
+			s.fg = ""
+		case code >= 40 && code <= 47:
+			s.bg = basicColorNames[code-40]
+		case code == 49:
+			s.bg = ""
+		case code == 59:
+			s.ul = ""
+		case code >= 90 && code <= 97:
+			s.fg = basicColorNames[code-90+8]
+		case code >= 100 && code <= 107:
+			s.bg = basicColorNames[code-100+8]
+		case code == 38 || code == 48 || code == 58:
+			var color string
+			if len(sub) > 1 {
+				color = extendedColor(sub[1:])
+			} else {
+				var used int
+				color, used = extendedColorFields(fields[i+1:])
+				i += used
+			}
+			switch code {
+			case 38:
+				s.fg = color
+			case 48:
+				s.bg = color
+			default:
+				s.ul = color
+			}
+		default:
+			for _, a := range attrNames {
+				if a.code == code || (code == 6 && a.attr == attrBlink) {
+					s.attrs |= a.attr
+				}
+			}
+		}
+	}
+}
+
+// extendedColorFields decodes the semicolon form of an extended color
+// ("5;196" or "2;255;0;0") and returns the color and the number of fields
+// consumed.
+func extendedColorFields(fields []string) (string, int) {
+	if len(fields) == 0 {
+		return "", 0
+	}
+	switch fields[0] {
+	case "5":
+		if len(fields) < 2 {
+			return "", len(fields)
+		}
+		return paletteColor(atoiOr(fields[1], 0)), 2
+	case "2":
+		if len(fields) < 4 {
+			return "", len(fields)
+		}
+		return rgbColor(atoiOr(fields[1], 0), atoiOr(fields[2], 0), atoiOr(fields[3], 0)), 4
+	}
+	return "", 1
+}
+
+// extendedColor decodes the colon form of an extended color ("5:196",
+// "2::255:0:0" or "2:255:0:0").
+func extendedColor(sub []string) string {
+	switch sub[0] {
+	case "5":
+		if len(sub) >= 2 {
+			return paletteColor(atoiOr(sub[1], 0))
+		}
+	case "2":
+		rgb := sub[1:]
+		if len(rgb) >= 4 {
+			rgb = rgb[1:] // skip the color space id
+		}
+		if len(rgb) >= 3 {
+			return rgbColor(atoiOr(rgb[0], 0), atoiOr(rgb[1], 0), atoiOr(rgb[2], 0))
+		}
+	}
+	return ""
+}
+
+// paletteColor returns the canonical color for xterm palette index n.
+func paletteColor(n int) string {
+	if n < 0 || n > 255 {
+		return ""
+	}
+	if n < 16 {
+		return basicColorNames[n]
+	}
+	if n < 232 {
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return rgbColor(level(n/36), level(n/6%6), level(n%6))
+	}
+	gray := 8 + (n-232)*10
+	return rgbColor(gray, gray, gray)
+}
+
+// rgbColor formats a 24-bit color as "#rrggbb".
+func rgbColor(r, g, b int) string {
+	clamp := func(v int) int { return min(max(v, 0), 255) }
+	return fmt.Sprintf("#%02x%02x%02x", clamp(r), clamp(g), clamp(b))
+}
+
+func atoiOr(s string, def int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// styledCell is one terminal cell: a grapheme cluster and its style.
+// Continuation cells of wide graphemes hold "" and the lead cell's style.
+type styledCell struct {
+	text  string
+	style cellStyle
+}
+
+// styledScreen is a view laid out as styled cells, one row per line.
+type styledScreen struct {
+	rows [][]styledCell
+}
+
+// parseStyledScreen interprets the SGR sequences in view and lays the text
+// out as styled cells. Other escape sequences (cursor movement, OSC
+// hyperlinks, ...) are dropped. Zero-width clusters attach to the previous
+// cell, as in cellGrid.
+func parseStyledScreen(view string) styledScreen {
+	var scr styledScreen
+	var row []styledCell
+	var style cellStyle
+	state := -1
+	for i := 0; i < len(view); {
+		switch c := view[i]; {
+		case c == '\n':
+			scr.rows = append(scr.rows, row)
+			row = nil
+			state = -1
+			i++
+			continue
+		case c == 0x1b:
+			n, params, final := scanEscape(view[i:])
+			if final == 'm' {
+				style.applySGR(params)
+			}
+			i += n
+			state = -1
+			continue
+		}
+
+		var cluster string
+		var width int
+		cluster, _, width, state = uniseg.FirstGraphemeClusterInString(view[i:], state)
+		i += len(cluster)
+		if width == 0 {
+			if len(row) > 0 {
+				row[len(row)-1].text += cluster
+			}
+			continue
+		}
+		row = append(row, styledCell{text: cluster, style: style})
+		for w := 1; w < width; w++ {
+			row = append(row, styledCell{style: style})
+		}
+	}
+	scr.rows = append(scr.rows, row)
+	return scr
+}
+
+// scanEscape measures the escape sequence at the start of s. For CSI
+// sequences it returns the parameter bytes and the final byte; other
+// sequences (OSC, two-byte escapes) return final == 0.
+func scanEscape(s string) (n int, params string, final byte) {
+	if len(s) < 2 {
+		return len(s), "", 0
+	}
+	switch s[1] {
+	case '[':
+		for j := 2; j < len(s); j++ {
+			if s[j] >= 0x40 && s[j] <= 0x7e {
+				return j + 1, s[2:j], s[j]
+			}
+		}
+		return len(s), "", 0
+	case ']', 'P', '_', '^':
+		// String sequences end with BEL or ST (ESC \).
+		for j := 2; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return j + 1, "", 0
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2, "", 0
+			}
+		}
+		return len(s), "", 0
+	}
+	return 2, "", 0
+}
+
+// text returns row as plain text.
+func (scr styledScreen) text(row int) string {
+	var b strings.Builder
+	for _, c := range scr.rows[row] {
+		b.WriteString(c.text)
+	}
+	return b.String()
+}
+
+// styleAt returns the style of cell col on row; cells past the end of the
+// line are plain.
+func (scr styledScreen) styleAt(row, col int) cellStyle {
+	if row < 0 || row >= len(scr.rows) || col < 0 || col >= len(scr.rows[row]) {
+		return cellStyle{}
+	}
+	return scr.rows[row][col].style
+}
@@ -0,0 +1,101 @@
+package tuitestkit
+
+import "testing"
+
+func TestApplySGR(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{"bold red", []string{"1;31"}, "bold fg=red"},
+		{"order independent", []string{"31;1"}, "bold fg=red"},
+		{"reset", []string{"1;31", "0"}, "plain"},
+		{"empty is reset", []string{"1", ""}, "plain"},
+		{"normal intensity", []string{"1;2;3", "22"}, "italic"},
+		{"bright colors", []string{"94;101"}, "fg=bright-blue bg=bright-red"},
+		{"palette basic", []string{"38;5;1"}, "fg=red"},
+		{"palette cube", []string{"38;5;196"}, "fg=#ff0000"},
+		{"palette gray", []string{"48;5;244"}, "bg=#808080"},
+		{"truecolor", []string{"38;2;255;0;0"}, "fg=#ff0000"},
+		{"colon truecolor", []string{"38:2::255:0:0"}, "fg=#ff0000"},
+		{"colon palette", []string{"48:5:21"}, "bg=#0000ff"},
+		{"codes after color", []string{"38;5;196;4"}, "underline fg=#ff0000"},
+		{"default fg", []string{"31;44", "39"}, "bg=blue"},
+		{"underline off", []string{"4", "4:0"}, "plain"},
+		{"unknown ignored", []string{"1;73"}, "bold"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s cellStyle
+			for _, p := range tt.params {
+				s.applySGR(p)
+			}
+			if got := s.String(); got != tt.want {
+				t.Errorf("style = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCellStyle_RoundTrip(t *testing.T) {
+	for _, in := range []string{"plain", "bold", "faint italic fg=#112233", "underline strike bg=bright-cyan"} {
+		s, err := parseCellStyle(in)
+		if err != nil {
+			t.Fatalf("parseCellStyle(%q): %v", in, err)
+		}
+		if s.String() != in {
+			t.Errorf("round trip %q = %q", in, s.String())
+		}
+	}
+	if _, err := parseCellStyle("bold sparkly"); err == nil {
+		t.Error("expected an error for an unknown attribute")
+	}
+}
+
+func TestParseStyledScreen(t *testing.T) {
+	scr := parseStyledScreen("a\x1b[1m世\x1b[0mb\n\x1b]8;;http://x\x07é\x1b]8;;\x07")
+	if len(scr.rows) != 2 {
+		t.Fatalf("rows = %d", len(scr.rows))
+	}
+	if got := scr.text(0); got != "a世b" {
+		t.Errorf("row 0 = %q", got)
+	}
+	if len(scr.rows[0]) != 4 {
+		t.Errorf("wide char should take two cells, got %d cells", len(scr.rows[0]))
+	}
+	if scr.styleAt(0, 1).attrs != attrBold || scr.styleAt(0, 2).attrs != attrBold {
+		t.Errorf("both cells of the wide char should be bold")
+	}
+	if !scr.styleAt(0, 3).isPlain() {
+		t.Errorf("cell after reset should be plain")
+	}
+	if got := scr.text(1); got != "é" {
+		t.Errorf("row 1 = %q, hyperlinks should be dropped", got)
+	}
+	if !scr.styleAt(5, 5).isPlain() {
+		t.Error("cells outside the screen should be plain")
+	}
+}
+
+func TestScanEscape(t *testing.T) {
+	tests := []struct {
+		in     string
+		n      int
+		params string
+		final  byte
+	}{
+		{"\x1b[1;31mx", 7, "1;31", 'm'},
+		{"\x1b[2Kx", 4, "2", 'K'},
+		{"\x1b]0;title\x07x", 10, "", 0},
+		{"\x1b]0;title\x1b\\x", 11, "", 0},
+		{"\x1b7x", 2, "", 0},
+		{"\x1b[12", 4, "", 0},
+	}
+	for _, tt := range tests {
+		n, params, final := scanEscape(tt.in)
+		if n != tt.n || params != tt.params || final != tt.final {
+			t.Errorf("scanEscape(%q) = %d, %q, %q; want %d, %q, %q", tt.in, n, params, final, tt.n, tt.params, tt.final)
+		}
+	}
+}
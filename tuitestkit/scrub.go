@@ -0,0 +1,174 @@
+package tuitestkit
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Scrubber replaces nondeterministic content (timestamps, IDs, spinner
+// frames) in a view before it is snapshotted. Built-in scrubbers replace each
+// match with a placeholder of the same display width, so columns and borders
+// stay aligned in the golden file.
+type Scrubber struct {
+	// Name identifies the scrubber in placeholders and golden file metadata.
+	Name  string
+	apply func(string) string
+}
+
+// Scrub applies the scrubber to the text of view. Escape sequences are left
+// untouched, so a pattern such as ScrubDurations cannot match inside
+// "\x1b[0m"; each run of text between two sequences is scrubbed on its own.
+func (s Scrubber) Scrub(view string) string {
+	if s.apply == nil {
+		return view
+	}
+	if !strings.Contains(view, "\x1b") {
+		return s.apply(view)
+	}
+	var b strings.Builder
+	for i := 0; i < len(view); {
+		if view[i] == 0x1b {
+			n, _, _ := scanEscape(view[i:])
+			b.WriteString(view[i : i+n])
+			i += n
+			continue
+		}
+		j := strings.IndexByte(view[i:], 0x1b)
+		if j < 0 {
+			j = len(view) - i
+		}
+		b.WriteString(s.apply(view[i : i+j]))
+		i += j
+	}
+	return b.String()
+}
+
+// WithScrubbers applies scrubbers, in order, to the view before comparison
+// and before writing the golden file:
+//
+//	kit.SnapshotView(t, m, "status", kit.WithScrubbers(kit.ScrubRFC3339(), kit.ScrubUUIDs()))
+func WithScrubbers(scrubbers ...Scrubber) SnapshotOption {
+	return func(c *snapshotConfig) {
+		c.scrubbers = append(c.scrubbers, scrubbers...)
+	}
+}
+
+// scrub runs every configured scrubber over content.
+func (c snapshotConfig) scrub(content string) string {
+	for _, s := range c.scrubbers {
+		content = s.Scrub(content)
+	}
+	return content
+}
+
+// ScrubFunc wraps an arbitrary replacement function as a Scrubber. fn is
+// responsible for keeping the layout intact.
+func ScrubFunc(name string, fn func(view string) string) Scrubber {
+	return Scrubber{Name: name, apply: fn}
+}
+
+// ScrubRegex replaces every match of pattern. With an empty replacement each
+// match becomes a same-width placeholder such as "<name>___" (or "*" runs for
+// matches narrower than the tag). A non-empty replacement may reference
+// submatches ($1, ${name}) and is padded with spaces or truncated to the
+// width of the match. ScrubRegex panics if pattern does not compile.
+func ScrubRegex(name, pattern, replacement string) Scrubber {
+	re := regexp.MustCompile(pattern)
+	return Scrubber{Name: name, apply: func(view string) string {
+		return replaceSameWidth(re, view, func(sub []int) string {
+			if replacement == "" {
+				return Placeholder(name, ansi.StringWidth(view[sub[0]:sub[1]]))
+			}
+			return string(re.ExpandString(nil, replacement, view, sub))
+		})
+	}}
+}
+
+// replaceSameWidth replaces every match of re in s with repl(submatch indexes)
+// fitted to the display width of the match.
+func replaceSameWidth(re *regexp.Regexp, s string, repl func(sub []int) string) string {
+	var b strings.Builder
+	last := 0
+	for _, sub := range re.FindAllStringSubmatchIndex(s, -1) {
+		start, end := sub[0], sub[1]
+		match := s[start:end]
+		b.WriteString(s[last:start])
+		b.WriteString(fitWidth(repl(sub), ansi.StringWidth(match)))
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// fitWidth pads s with spaces or truncates it to exactly width cells.
+func fitWidth(s string, width int) string {
+	if w := ansi.StringWidth(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	s = ansi.Truncate(s, width, "")
+	return s + strings.Repeat(" ", width-ansi.StringWidth(s))
+}
+
+// Placeholder returns a marker for scrubbed content exactly width cells wide:
+// "<name>" padded with "_", or a run of "*" when the tag does not fit.
+func Placeholder(name string, width int) string {
+	tag := "<" + name + ">"
+	if len(tag) > width {
+		return strings.Repeat("*", width)
+	}
+	return tag + strings.Repeat("_", width-len(tag))
+}
+
+// --- Built-in scrubbers ---
+
+// ScrubRFC3339 replaces RFC 3339 timestamps ("2024-05-01T12:30:00Z",
+// "2024-05-01 12:30:00.123+02:00") with a "<time>" placeholder.
+func ScrubRFC3339() Scrubber {
+	return ScrubRegex("time", `\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`, "")
+}
+
+// ScrubDurations replaces Go-style and relative durations ("1h30m",
+// "250ms", "2.5s", "3m ago", "5 minutes") with a "<dur>" placeholder, or "*"
+// runs for short ones.
+func ScrubDurations() Scrubber {
+	return ScrubRegex("dur",
+		`\b\d+(\.\d+)?(ns|us|µs|ms|s|m|h|d|w)(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))*\b`+
+			`|\b\d+ (second|minute|hour|day|week|month|year)s?\b`, "")
+}
+
+// ScrubUUIDs replaces UUIDs with a "<uuid>" placeholder.
+func ScrubUUIDs() Scrubber {
+	return ScrubRegex("uuid", `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`, "")
+}
+
+// spinnerFrames are the glyph sets of the bubbles spinner package that are
+// distinctive enough to replace anywhere in a view. Line ("|/-\"), Pulse
+// (block shades) and Ellipsis (dots) are left out: they collide with
+// ordinary text and borders.
+var spinnerFrames = [][]string{
+	{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}, // MiniDot
+	{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"},           // Dot
+	{"⢄", "⢂", "⢁", "⡁", "⡈", "⡐", "⡠"},                // Jump
+	{"∙∙∙", "●∙∙", "∙●∙", "∙∙●"},                       // Points
+	{"🌍", "🌎", "🌏"},                                    // Globe
+	{"🌑", "🌒", "🌓", "🌔", "🌕", "🌖", "🌗", "🌘"},           // Moon
+	{"🙈", "🙉", "🙊"},                                    // Monkey
+	{"▱▱▱", "▰▱▱", "▰▰▱", "▰▰▰"},                       // Meter
+	{"☱", "☲", "☴"},                                    // Hamburger
+}
+
+// ScrubSpinners replaces every frame of the bubbles spinner glyph sets
+// (MiniDot, Dot, Jump, Points, Globe, Moon, Monkey, Meter, Hamburger) with
+// the first frame of its set, so a spinner snapshots the same on every tick.
+func ScrubSpinners() Scrubber {
+	var pairs []string
+	for _, frames := range spinnerFrames {
+		for _, f := range frames[1:] {
+			pairs = append(pairs, f, frames[0])
+		}
+	}
+	r := strings.NewReplacer(pairs...)
+	return Scrubber{Name: "spinner", apply: r.Replace}
+}
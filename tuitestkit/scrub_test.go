@@ -0,0 +1,148 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPlaceholder(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{"uuid", 10, "<uuid>____"},
+		{"uuid", 6, "<uuid>"},
+		{"uuid", 3, "***"},
+		{"dur", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := Placeholder(tt.name, tt.width); got != tt.want {
+			t.Errorf("Placeholder(%q, %d) = %q, want %q", tt.name, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestScrubRFC3339(t *testing.T) {
+	in := "│ updated 2024-05-01T12:30:00Z │\n│ created 2024-05-01 12:30:00.123+02:00 │"
+	want := "│ updated <time>______________ │\n│ created <time>_______________________ │"
+	if got := ScrubRFC3339().Scrub(in); got != want {
+		t.Errorf("Scrub = %q, want %q", got, want)
+	}
+}
+
+func TestScrubDurations(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"took 1h30m5s", "took <dur>__"},
+		{"3m ago", "** ago"},
+		{"in 250ms", "in <dur>"},
+		{"in 5s", "in **"},
+		{"elapsed 2.5s", "elapsed ****"},
+		{"5 minutes ago", "<dur>____ ago"},
+		{"80x24 items", "80x24 items"},
+	}
+	for _, tt := range tests {
+		if got := ScrubDurations().Scrub(tt.in); got != tt.want {
+			t.Errorf("Scrub(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestScrubUUIDs(t *testing.T) {
+	in := "id: 123e4567-e89b-12d3-a456-426614174000 |"
+	got := ScrubUUIDs().Scrub(in)
+	if ansi.StringWidth(got) != ansi.StringWidth(in) {
+		t.Errorf("width changed: %q -> %q", in, got)
+	}
+	if got != "id: <uuid>______________________________ |" {
+		t.Errorf("Scrub = %q", got)
+	}
+}
+
+func TestScrubSpinners(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"⠹ Loading", "⠋ Loading"},
+		{"⣟ Syncing", "⣾ Syncing"},
+		{"∙●∙ wait", "∙∙∙ wait"},
+		{"🌗 moon", "🌑 moon"},
+		{"▰▰▱ 2/3", "▱▱▱ 2/3"},
+		{"| plain - text /", "| plain - text /"},
+	}
+	for _, tt := range tests {
+		if got := ScrubSpinners().Scrub(tt.in); got != tt.want {
+			t.Errorf("Scrub(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestScrubRegex_Replacement(t *testing.T) {
+	s := ScrubRegex("tmp", `/tmp/\w+/`, "$$TMP/")
+	if got := s.Scrub("at /tmp/abc123/file"); got != "at $TMP/       file" {
+		t.Errorf("padded replacement = %q", got)
+	}
+	s = ScrubRegex("pid", `pid=(\d+)`, "pid=${1}xxxxxxxx")
+	if got := s.Scrub("pid=42 ok"); got != "pid=42 ok" {
+		t.Errorf("truncated replacement = %q", got)
+	}
+}
+
+func TestScrubFunc(t *testing.T) {
+	s := ScrubFunc("upper", func(v string) string { return "X" + v[1:] })
+	if got := s.Scrub("abc"); got != "Xbc" {
+		t.Errorf("Scrub = %q", got)
+	}
+	if got := (Scrubber{}).Scrub("abc"); got != "abc" {
+		t.Errorf("zero Scrubber should be a no-op, got %q", got)
+	}
+}
+
+func TestSnapshot_WithScrubbers(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	scrub := WithScrubbers(ScrubRFC3339(), ScrubSpinners())
+	SnapshotStr(t, "⠙ synced 2024-05-01T12:30:00Z", "scrubbed", scrub)
+
+	data, err := os.ReadFile(filepath.Join(dir, "scrubbed.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "⠋ synced <time>______________"; string(data) != want {
+		t.Errorf("golden = %q, want %q", data, want)
+	}
+
+	// A later run with different volatile content still matches.
+	UpdateSnapshots = false
+	ft := &fakeT{}
+	snapshot(ft, "⠸ synced 2031-12-24T08:00:59Z", "scrubbed", 1, scrub)
+	if ft.failed {
+		t.Errorf("scrubbed content should match: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshot_ScrubbersKeepStyles(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	view := "\x1b[1;38;5;42mdone\x1b[0m in \x1b[2m250ms\x1b[0m id 123e4567-e89b-12d3-a456-426614174000"
+	scrub := WithScrubbers(ScrubDurations(), ScrubUUIDs())
+	for mode, want := range map[string]string{
+		ansiRaw:    "\x1b[1;38;5;42mdone\x1b[0m in \x1b[2m<dur>\x1b[0m id <uuid>______________________________",
+		ansiStyled: "done in <dur> id <uuid>______________________________\n-- styles --\n[1:0-4 bold fg=#00d787]\n[1:8-13 faint]",
+	} {
+		name := "scrub-" + mode
+		snapshot(t, view, name, 1, withANSIMode(mode, []SnapshotOption{scrub})...)
+		data, err := os.ReadFile(filepath.Join(dir, name+".golden"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s golden = %q, want %q", mode, data, want)
+		}
+	}
+}
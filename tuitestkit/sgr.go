@@ -0,0 +1,132 @@
+package tuitestkit
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CanonicalSGR rewrites the SGR (color and attribute) escape sequences in view
+// into one canonical form, so views that look the same produce the same bytes
+// regardless of how the renderer encoded them:
+//
+//   - parameters in a fixed order: attributes, then foreground, background,
+//     underline color ("\x1b[31;1m" and "\x1b[1;31m" both become "\x1b[1;31m")
+//   - one sequence per style change, emitted right before the text it styles;
+//     redundant resets and sequences with no visible effect are dropped
+//   - 256-palette and 24-bit colors written as 38;5;n when the color is in the
+//     xterm palette, 38;2;r;g;b otherwise
+//   - styles reset before each newline and re-applied on the next line
+//
+// Other escape sequences (cursor movement, OSC hyperlinks) are kept verbatim.
+// The raw snapshot functions canonicalize both the view and the golden file
+// before comparing, so a lipgloss or termenv upgrade that changes only the
+// encoding does not invalidate raw golden files.
+func CanonicalSGR(view string) string {
+	var b strings.Builder
+	var want, have cellStyle
+	for i := 0; i < len(view); {
+		switch c := view[i]; c {
+		case '\n':
+			if !have.isPlain() {
+				b.WriteString("\x1b[0m")
+				have = cellStyle{}
+			}
+			b.WriteByte('\n')
+			i++
+			continue
+		case 0x1b:
+			n, params, final := scanEscape(view[i:])
+			if final == 'm' {
+				want.applySGR(params)
+			} else {
+				b.WriteString(view[i : i+n])
+			}
+			i += n
+			continue
+		}
+		if want != have {
+			b.WriteString(sgrTransition(have, want))
+			have = want
+		}
+		j := i + 1
+		for j < len(view) && view[j] != '\n' && view[j] != 0x1b {
+			j++
+		}
+		b.WriteString(view[i:j])
+		i = j
+	}
+	if !have.isPlain() {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// sgrTransition returns the sequence that changes the terminal style from
+// `from` to `to`: only the added attributes and changed colors when nothing is
+// removed, otherwise a reset followed by the full target style.
+func sgrTransition(from, to cellStyle) string {
+	if to.isPlain() {
+		return "\x1b[0m"
+	}
+	removes := from.attrs&^to.attrs != 0 ||
+		(from.fg != "" && to.fg == "") ||
+		(from.bg != "" && to.bg == "") ||
+		(from.ul != "" && to.ul == "")
+	var params []string
+	if removes {
+		params = append(params, "0")
+		from = cellStyle{}
+	}
+	for _, a := range attrNames {
+		if to.attrs&a.attr != 0 && from.attrs&a.attr == 0 {
+			params = append(params, strconv.Itoa(a.code))
+		}
+	}
+	if to.fg != from.fg {
+		params = append(params, colorSGR(to.fg, 30, 90, "38"))
+	}
+	if to.bg != from.bg {
+		params = append(params, colorSGR(to.bg, 40, 100, "48"))
+	}
+	if to.ul != from.ul {
+		params = append(params, colorSGR(to.ul, -1, -1, "58"))
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// colorSGR encodes a canonical color. Basic colors use the 30–37 / 90–97
+// style codes given by base and bright (underline colors have none, pass -1);
+// other colors use the extended code ext with a palette index when one
+// matches exactly.
+func colorSGR(color string, base, bright int, ext string) string {
+	for i, name := range basicColorNames {
+		if name != color {
+			continue
+		}
+		switch {
+		case base < 0:
+			return ext + ";5;" + strconv.Itoa(i)
+		case i < 8:
+			return strconv.Itoa(base + i)
+		default:
+			return strconv.Itoa(bright + i - 8)
+		}
+	}
+	if n, ok := paletteIndex[color]; ok {
+		return ext + ";5;" + strconv.Itoa(n)
+	}
+	r, _ := strconv.ParseUint(color[1:3], 16, 8)
+	g, _ := strconv.ParseUint(color[3:5], 16, 8)
+	bl, _ := strconv.ParseUint(color[5:7], 16, 8)
+	return ext + ";2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(bl))
+}
+
+// paletteIndex maps the "#rrggbb" colors of xterm palette entries 16–255 to
+// their index. The gray ramp and the cube share no colors.
+var paletteIndex = func() map[string]int {
+	m := make(map[string]int, 240)
+	for n := 16; n < 256; n++ {
+		m[paletteColor(n)] = n
+	}
+	return m
+}()
@@ -0,0 +1,72 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCanonicalSGR(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "abc", "abc"},
+		{"param order", "\x1b[31;1mx\x1b[0m", "\x1b[1;31mx\x1b[0m"},
+		{"split sequences", "\x1b[1m\x1b[31mx\x1b[m", "\x1b[1;31mx\x1b[0m"},
+		{"redundant resets", "\x1b[0m\x1b[0mx\x1b[0m\x1b[0m", "x"},
+		{"no-op change", "\x1b[1mab\x1b[1mc\x1b[0m", "\x1b[1mabc\x1b[0m"},
+		{"unused style", "a\x1b[1m\x1b[0mb", "ab"},
+		{"truecolor in palette", "\x1b[38;2;255;0;0mx\x1b[0m", "\x1b[38;5;196mx\x1b[0m"},
+		{"truecolor off palette", "\x1b[48;2;1;2;3mx\x1b[0m", "\x1b[48;2;1;2;3mx\x1b[0m"},
+		{"palette basic", "\x1b[38;5;9mx\x1b[0m", "\x1b[91mx\x1b[0m"},
+		{"underline color", "\x1b[4;58:2::255:0:0mx\x1b[0m", "\x1b[4;58;5;196mx\x1b[0m"},
+		{"add only", "\x1b[1ma\x1b[31mb\x1b[0m", "\x1b[1ma\x1b[31mb\x1b[0m"},
+		{"remove resets", "\x1b[1;31ma\x1b[22mb\x1b[0m", "\x1b[1;31ma\x1b[0;31mb\x1b[0m"},
+		{"reset per line", "\x1b[32ma\nb\x1b[0m", "\x1b[32ma\x1b[0m\n\x1b[32mb\x1b[0m"},
+		{"unterminated style", "\x1b[1mx", "\x1b[1mx\x1b[0m"},
+		{"other escapes kept", "\x1b]8;;http://x\x07\x1b[1mx\x1b[0m\x1b[2K", "\x1b]8;;http://x\x07\x1b[1mx\x1b[2K\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalSGR(tt.in); got != tt.want {
+				t.Errorf("CanonicalSGR(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalSGR_Idempotent(t *testing.T) {
+	in := "\x1b[31;1;48;5;236mhead\x1b[22m er\x1b[0m\n\x1b[3;38;2;10;20;30mtail"
+	once := CanonicalSGR(in)
+	if twice := CanonicalSGR(once); twice != once {
+		t.Errorf("not idempotent:\n%q\n%q", once, twice)
+	}
+	if StripANSI(once) != StripANSI(in) {
+		t.Errorf("text changed: %q", StripANSI(once))
+	}
+	if StyledText(once) != StyledText(in) {
+		t.Errorf("styles changed:\n%s\n---\n%s", StyledText(once), StyledText(in))
+	}
+}
+
+func TestSnapshotStrRaw_EquivalentEncodingMatches(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	// A golden file recorded by an older renderer, before canonicalization.
+	writeFile(t, filepath.Join(dir, "legacy-raw.golden"), "\x1b[31;1mError\x1b[0m\x1b[0m")
+
+	ft := &fakeT{}
+	snapshot(ft, "\x1b[1m\x1b[38;5;1mError\x1b[m", "legacy-raw", 1, withANSIMode(ansiRaw, nil)...)
+	if ft.failed {
+		t.Errorf("equivalent SGR should match: %s", ft.lastErr)
+	}
+
+	UpdateSnapshots = true
+	SnapshotStrRaw(t, "\x1b[31;1mError\x1b[0m\x1b[0m", "written-raw")
+	data, _ := os.ReadFile(filepath.Join(dir, "written-raw.golden"))
+	if string(data) != "\x1b[1;31mError\x1b[0m" {
+		t.Errorf("golden = %q, want canonical SGR", data)
+	}
+}
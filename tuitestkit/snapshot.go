@@ -1,11 +1,14 @@
 package tuitestkit
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,9 +16,23 @@ import (
 
 // UpdateSnapshots controls whether snapshot functions overwrite golden files
 // instead of comparing against them. Set via UPDATE_SNAPSHOTS=1 environment
-// variable, or directly in test code.
+// variable, the -update-snapshots test flag, or directly in test code.
 var UpdateSnapshots bool
 
+// UpdateSnapshotsFilter restricts UpdateSnapshots to snapshots whose name
+// matches one of these path.Match patterns (e.g. "board-*"). A pattern
+// without a slash also matches the last segment of a name, so "board-*"
+// selects "TestX/board-1". Snapshots that do not match are compared as usual,
+// so unrelated drift still fails. Empty means every snapshot is updated. Set
+// via UPDATE_SNAPSHOTS=<patterns> or -update-snapshots=<patterns>
+// (comma-separated); SnapshotMain warns about patterns that matched nothing.
+var UpdateSnapshotsFilter []string
+
+// CreateMissingSnapshots writes golden files that do not exist yet, without
+// ever overwriting existing ones. Set via CREATE_SNAPSHOTS=1 or the
+// -create-snapshots test flag.
+var CreateMissingSnapshots bool
+
 // snapshotBaseDir overrides the automatic path resolution for tests.
 // When empty (default), snapshot functions use runtime.Caller to determine
 // the test file's directory and place golden files in testdata/snapshots/.
@@ -23,9 +40,115 @@ var UpdateSnapshots bool
 var snapshotBaseDir string
 
 func init() {
-	if os.Getenv("UPDATE_SNAPSHOTS") == "1" {
-		UpdateSnapshots = true
+	if v := os.Getenv("UPDATE_SNAPSHOTS"); v != "" {
+		if err := setUpdateSnapshots(v); err != nil {
+			fmt.Fprintf(os.Stderr, "tuitestkit: ignoring UPDATE_SNAPSHOTS: %v\n", err)
+		}
+	}
+	if os.Getenv("CREATE_SNAPSHOTS") == "1" {
+		CreateMissingSnapshots = true
+	}
+	if testing.Testing() {
+		flag.Var(updateSnapshotsFlag{}, "update-snapshots", "tuitestkit: update golden files; optionally only those matching comma-separated patterns (-update-snapshots='board-*')")
+		flag.BoolVar(&CreateMissingSnapshots, "create-snapshots", CreateMissingSnapshots, "tuitestkit: create missing golden files, never overwrite existing ones")
+	}
+}
+
+// setUpdateSnapshots applies an UPDATE_SNAPSHOTS / -update-snapshots value:
+// "1", "true", "yes", "on" or "all" updates everything, "0", "false", "no"
+// or "off" disables updates, and anything else is a comma-separated list of
+// name patterns.
+func setUpdateSnapshots(v string) error {
+	updateFilterMatched.Lock()
+	updateFilterMatched.patterns = nil
+	updateFilterMatched.Unlock()
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "0", "false", "no", "off":
+		UpdateSnapshots, UpdateSnapshotsFilter = false, nil
+		return nil
+	case "1", "true", "yes", "on", "all":
+		UpdateSnapshots, UpdateSnapshotsFilter = true, nil
+		return nil
+	}
+	var patterns []string
+	for _, p := range strings.Split(v, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid snapshot pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, p)
+	}
+	UpdateSnapshots, UpdateSnapshotsFilter = true, patterns
+	return nil
+}
+
+// updateSnapshotsFlag exposes setUpdateSnapshots as a flag that also works
+// without a value (-update-snapshots).
+type updateSnapshotsFlag struct{}
+
+func (updateSnapshotsFlag) String() string {
+	if !UpdateSnapshots {
+		return "false"
+	}
+	if len(UpdateSnapshotsFilter) == 0 {
+		return "true"
+	}
+	return strings.Join(UpdateSnapshotsFilter, ",")
+}
+
+func (updateSnapshotsFlag) Set(v string) error { return setUpdateSnapshots(v) }
+
+func (updateSnapshotsFlag) IsBoolFlag() bool { return true }
+
+// updateFilterMatched records which UpdateSnapshotsFilter patterns selected
+// at least one snapshot, for the warning in SnapshotMain.
+var updateFilterMatched struct {
+	sync.Mutex
+	patterns map[string]bool
+}
+
+// shouldUpdateSnapshot reports whether the snapshot called name is to be
+// overwritten rather than compared.
+func shouldUpdateSnapshot(name string) bool {
+	if !UpdateSnapshots {
+		return false
+	}
+	if len(UpdateSnapshotsFilter) == 0 {
+		return true
+	}
+	matched := false
+	updateFilterMatched.Lock()
+	defer updateFilterMatched.Unlock()
+	for _, p := range UpdateSnapshotsFilter {
+		if matchSnapshotPattern(p, name) {
+			if updateFilterMatched.patterns == nil {
+				updateFilterMatched.patterns = map[string]bool{}
+			}
+			updateFilterMatched.patterns[p] = true
+			matched = true
+		}
 	}
+	return matched
+}
+
+// unmatchedUpdatePatterns returns the UpdateSnapshotsFilter patterns that
+// have not selected any snapshot so far.
+func unmatchedUpdatePatterns() []string {
+	if !UpdateSnapshots {
+		return nil
+	}
+	updateFilterMatched.Lock()
+	defer updateFilterMatched.Unlock()
+	var out []string
+	for _, p := range UpdateSnapshotsFilter {
+		if !updateFilterMatched.patterns[p] {
+			out = append(out, p)
+		}
+	}
+	return out
 }
 
 // snapshotPath returns the full path for a golden file named `name`.
@@ -33,147 +156,338 @@ func init() {
 // Otherwise it walks up the call stack (skip frames) to find the caller's
 // source file directory and appends testdata/snapshots/.
 func snapshotPath(name string, callerSkip int) string {
-	base := snapshotBaseDir
-	if base == "" {
-		_, file, _, ok := runtime.Caller(callerSkip)
-		if !ok {
-			panic("tuitestkit: cannot determine caller file for snapshot path")
-		}
-		base = filepath.Join(filepath.Dir(file), "testdata", "snapshots")
+	return filepath.Join(snapshotDir(callerSkip+1), name+".golden")
+}
+
+// snapshotDir returns the directory golden files are stored in: snapshotBaseDir
+// when set, otherwise testdata/snapshots/ next to the source file found
+// callerSkip frames up the stack.
+func snapshotDir(callerSkip int) string {
+	if snapshotBaseDir != "" {
+		return snapshotBaseDir
+	}
+	_, file, _, ok := runtime.Caller(callerSkip)
+	if !ok {
+		panic("tuitestkit: cannot determine caller file for snapshot path")
 	}
-	return filepath.Join(base, name+".golden")
+	return filepath.Join(filepath.Dir(file), "testdata", "snapshots")
 }
 
 // SnapshotView captures model.View(), strips ANSI escape codes, and compares
 // (or updates) the golden file named `name`.
-func SnapshotView(t *testing.T, model tea.Model, name string) {
+func SnapshotView(t *testing.T, model tea.Model, name string, opts ...SnapshotOption) {
 	t.Helper()
-	view := StripANSI(model.View())
-	snapshot(t, view, name, 3)
+	view := model.View()
+	snapshot(t, StripANSI(view), name, 3, withStripped(view, opts)...)
 }
 
 // SnapshotViewRaw captures model.View() with raw ANSI codes intact and
-// compares (or updates) the golden file named `name`.
-func SnapshotViewRaw(t *testing.T, model tea.Model, name string) {
+// compares (or updates) the golden file named `name`. SGR sequences are
+// canonicalized (see CanonicalSGR) on both sides, so byte-different but
+// equivalent encodings compare equal.
+func SnapshotViewRaw(t *testing.T, model tea.Model, name string, opts ...SnapshotOption) {
 	t.Helper()
-	snapshot(t, model.View(), name, 3)
+	snapshot(t, model.View(), name, 3, withANSIMode(ansiRaw, opts)...)
 }
 
 // SnapshotStr compares a pre-rendered view string (after ANSI stripping)
 // against the golden file named `name`.
-func SnapshotStr(t *testing.T, view string, name string) {
+func SnapshotStr(t *testing.T, view string, name string, opts ...SnapshotOption) {
 	t.Helper()
-	snapshot(t, StripANSI(view), name, 3)
+	snapshot(t, StripANSI(view), name, 3, withStripped(view, opts)...)
 }
 
 // SnapshotStrRaw compares a pre-rendered view string (raw, with ANSI codes)
 // against the golden file named `name`.
-func SnapshotStrRaw(t *testing.T, view string, name string) {
+func SnapshotStrRaw(t *testing.T, view string, name string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, view, name, 3, withANSIMode(ansiRaw, opts)...)
+}
+
+// --- Automatic naming ---
+
+// SnapshotViewAuto is SnapshotView with the golden file name derived from the
+// test name. See AutoSnapshotName.
+func SnapshotViewAuto(t *testing.T, model tea.Model, opts ...SnapshotOption) {
+	t.Helper()
+	view := model.View()
+	snapshot(t, StripANSI(view), AutoSnapshotName(t), 3, withStripped(view, opts)...)
+}
+
+// SnapshotViewRawAuto is SnapshotViewRaw with the golden file name derived
+// from the test name. See AutoSnapshotName.
+func SnapshotViewRawAuto(t *testing.T, model tea.Model, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, model.View(), AutoSnapshotName(t), 3, withANSIMode(ansiRaw, opts)...)
+}
+
+// SnapshotStrAuto is SnapshotStr with the golden file name derived from the
+// test name. See AutoSnapshotName.
+func SnapshotStrAuto(t *testing.T, view string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, StripANSI(view), AutoSnapshotName(t), 3, withStripped(view, opts)...)
+}
+
+// SnapshotStrRawAuto is SnapshotStrRaw with the golden file name derived from
+// the test name. See AutoSnapshotName.
+func SnapshotStrRawAuto(t *testing.T, view string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, view, AutoSnapshotName(t), 3, withANSIMode(ansiRaw, opts)...)
+}
+
+// snapshotCounters counts automatically named snapshots per running test.
+var snapshotCounters = struct {
+	sync.Mutex
+	n map[string]int
+}{n: map[string]int{}}
+
+// AutoSnapshotName returns the next snapshot name for the running test:
+// t.Name() with one directory level per subtest, plus a per-test counter,
+// e.g. "TestBoard/empty_state-1", "TestBoard/empty_state-2". The counter
+// restarts every time the test runs, so -count=N reuses the same names.
+// Characters that are unsafe in file names are replaced with "_".
+func AutoSnapshotName(t *testing.T) string {
 	t.Helper()
-	snapshot(t, view, name, 3)
+	snapshotCounters.Lock()
+	defer snapshotCounters.Unlock()
+	key := t.Name()
+	if _, ok := snapshotCounters.n[key]; !ok {
+		t.Cleanup(func() {
+			snapshotCounters.Lock()
+			defer snapshotCounters.Unlock()
+			delete(snapshotCounters.n, key)
+		})
+	}
+	snapshotCounters.n[key]++
+	return fmt.Sprintf("%s-%d", sanitizeSnapshotName(key), snapshotCounters.n[key])
+}
+
+// sanitizeSnapshotName maps a test name onto a relative file path: subtest
+// separators become directories, anything outside [A-Za-z0-9._-] becomes "_",
+// and "." / ".." segments are neutralised.
+func sanitizeSnapshotName(name string) string {
+	segments := strings.Split(name, "/")
+	for i, seg := range segments {
+		seg = strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+				return r
+			}
+			return '_'
+		}, seg)
+		if seg == "" || strings.Trim(seg, ".") == "" {
+			seg = strings.Repeat("_", max(len(seg), 1))
+		}
+		segments[i] = seg
+	}
+	return strings.Join(segments, "/")
+}
+
+// snapshotWriters records which test wrote each golden file during this run,
+// so two tests updating the same path are caught instead of silently
+// overwriting each other.
+var snapshotWriters = struct {
+	sync.Mutex
+	owner map[string]string
+}{owner: map[string]string{}}
+
+// claimSnapshotPath records that test `test` writes path. Returns the name
+// of a different test that already wrote it, or "".
+func claimSnapshotPath(path string, test string) string {
+	snapshotWriters.Lock()
+	defer snapshotWriters.Unlock()
+	if owner, ok := snapshotWriters.owner[path]; ok && owner != test {
+		return owner
+	}
+	snapshotWriters.owner[path] = test
+	return ""
 }
 
 // snapshotT is the subset of testing.T used by the snapshot implementation.
 // Extracted as an interface so tests can intercept failure calls.
 type snapshotT interface {
 	Helper()
+	Name() string
 	Fatalf(format string, args ...any)
 	Errorf(format string, args ...any)
 }
 
-// snapshot is the core implementation shared by all Snapshot* functions.
-// callerSkip controls how many stack frames to skip when resolving the
-// snapshot path (only used when snapshotBaseDir is empty).
-func snapshot(t snapshotT, content string, name string, callerSkip int) {
+// SnapshotOption adjusts how a single snapshot is prepared before it is
+// compared or written, e.g. WithScrubbers. Every Snapshot* function accepts
+// options after its regular arguments.
+type SnapshotOption func(*snapshotConfig)
+
+// snapshotConfig is the result of applying a list of SnapshotOptions.
+type snapshotConfig struct {
+	scrubbers []Scrubber
+	masks     []snapshotMask
+	maskFill  rune
+
+	withHeader    bool
+	ansi          string
+	storyboard    bool
+	frames        []storyFrame // storyboard frames before joining, see withFrames
+	state         bool
+	source        string // the view before ANSI stripping, for the report
+	image         *ImageRenderer
+	width, height int
+	env           *RenderEnv
+}
+
+// newSnapshotConfig applies opts in order.
+func newSnapshotConfig(opts []SnapshotOption) snapshotConfig {
+	var cfg snapshotConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// prepare applies masks, then scrubbers, to content, and renders it in the
+// form stored for the ANSI mode: canonical SGR for raw snapshots, StyledText
+// for styled ones, SVG for images. Storyboards are prepared frame by frame.
+func (c snapshotConfig) prepare(content string) (string, error) {
+	if c.storyboard {
+		return c.prepareFrames(content)
+	}
+	return c.prepareView(content)
+}
+
+// prepareView is prepare for a single view.
+func (c snapshotConfig) prepareView(content string) (string, error) {
+	content, err := c.applyMasks(content)
+	if err != nil {
+		return "", err
+	}
+	content = c.scrub(content)
+	switch c.ansi {
+	case ansiRaw:
+		content = CanonicalSGR(content)
+	case ansiStyled:
+		content = StyledText(content)
+	case ansiImage:
+		content = c.renderImage(content)
+	}
+	return content, nil
+}
+
+// normalize brings golden file content into the form prepare produces, so
+// raw golden files recorded before canonicalization (or with another
+// renderer's encoding) still compare equal.
+func (c snapshotConfig) normalize(expected string) string {
+	if c.ansi == ansiRaw {
+		return CanonicalSGR(expected)
+	}
+	return expected
+}
+
+// diff explains a content mismatch in the form suited to the ANSI mode;
+// storyboards by their first diverging frame, state by field path.
+func (c snapshotConfig) diff(expected, actual string) string {
+	if c.storyboard {
+		return storyboardDiff(expected, actual, c.viewDiff)
+	}
+	return c.viewDiff(expected, actual)
+}
+
+// viewDiff is diff for a single view (or state).
+func (c snapshotConfig) viewDiff(expected, actual string) string {
+	if c.state {
+		return stateDiff(expected, actual)
+	}
+	switch c.ansi {
+	case ansiRaw:
+		return rawDiff(expected, actual)
+	case ansiStyled:
+		return styledDiff(expected, actual)
+	case ansiImage:
+		return imageDiff(expected, actual)
+	}
+	return unifiedDiff(expected, actual)
+}
+
+// snapshot is the core implementation shared by all Snapshot* functions,
+// which use the default Snapshotter. callerSkip controls how many stack
+// frames to skip when resolving the snapshot path (only used when
+// snapshotBaseDir is empty).
+func snapshot(t snapshotT, content string, name string, callerSkip int, opts ...SnapshotOption) {
 	t.Helper()
+	defaultSnapshotter.snapshotFile(t, content, name, defaultSnapshotter.ref(name, callerSkip), opts...)
+}
 
-	path := snapshotPath(name, callerSkip)
+// snapshotFile compares content against (or updates) the golden snapshot at
+// ref. Helpers that run snapshots from their own closures (subtests) resolve
+// the ref up front and call this directly.
+func (s *Snapshotter) snapshotFile(t snapshotT, content string, name string, ref goldenRef, opts ...SnapshotOption) {
+	t.Helper()
+	touchSnapshot(ref)
+	cfg := newSnapshotConfig(s.options(opts))
+	content, err := cfg.prepare(content)
+	if err != nil {
+		t.Fatalf("snapshot %q: %v", name, err)
+	}
+	header := cfg.header()
+	file := header.encode() + content
 
-	if UpdateSnapshots {
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0o755); err != nil {
-			t.Fatalf("snapshot: cannot create directory %s: %v", dir, err)
-		}
-		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
-			t.Fatalf("snapshot: cannot write golden file %s: %v", path, err)
-		}
+	overwrite, create := s.updates(name)
+	if overwrite {
+		recordSnapshot(t, name, ref, cfg, "updated", "", content, "")
+		writeSnapshot(t, file, name, ref)
 		return
 	}
 
-	expected, err := os.ReadFile(path)
+	expected, err := ref.read()
 	if err != nil {
 		if os.IsNotExist(err) {
-			t.Fatalf("snapshot %q: golden file not found at %s\nRun with UPDATE_SNAPSHOTS=1 to create it.", name, path)
+			if create {
+				recordSnapshot(t, name, ref, cfg, "created", "", content, "")
+				writeSnapshot(t, file, name, ref)
+				return
+			}
+			recordSnapshot(t, name, ref, cfg, "missing", "", content, "golden file not found")
+			t.Fatalf("snapshot %q: golden file not found at %s%s\nRun with UPDATE_SNAPSHOTS=1 (or CREATE_SNAPSHOTS=1 to only add missing files) to create it.", name, ref, pendingNote(ref, file))
 		}
 		t.Fatalf("snapshot %q: cannot read golden file: %v", name, err)
 	}
 
-	expectedStr := string(expected)
+	recorded, expectedStr, hasHeader := parseSnapshotHeader(expected)
+	expectedStr = cfg.normalize(expectedStr)
+	if hasHeader {
+		if err := checkSnapshotHeader(recorded, header); err != nil {
+			recordSnapshot(t, name, ref, cfg, "mismatch", expectedStr, content, "snapshot was "+err.Error())
+			t.Errorf("snapshot %q was %v\nRe-record it with UPDATE_SNAPSHOTS=1 if the change is intended.%s", name, err, pendingNote(ref, file))
+			return
+		}
+	}
 	if expectedStr == content {
+		recordSnapshot(t, name, ref, cfg, "match", expectedStr, content, "")
+		clearPendingSnapshot(ref)
 		return
 	}
 
-	diff := unifiedDiff(expectedStr, content)
-	t.Errorf("snapshot %q mismatch:\n%s", name, diff)
-}
-
-// unifiedDiff produces a simple line-by-line diff between expected and actual.
-// Lines prefixed with "-" are only in expected; "+" only in actual.
-// Unchanged lines are prefixed with " ". Every line includes a line number.
-func unifiedDiff(expected, actual string) string {
-	expLines := strings.Split(expected, "\n")
-	actLines := strings.Split(actual, "\n")
-
-	// LCS-based diff for accurate results.
-	lcs := lcsTable(expLines, actLines)
-	var b strings.Builder
-
-	b.WriteString("--- expected\n")
-	b.WriteString("+++ actual\n")
-
-	i, j := len(expLines), len(actLines)
-	// Build diff lines in reverse, then reverse the slice.
-	var lines []string
-	for i > 0 || j > 0 {
-		if i > 0 && j > 0 && expLines[i-1] == actLines[j-1] {
-			lines = append(lines, fmt.Sprintf(" %4d  %s", i, expLines[i-1]))
-			i--
-			j--
-		} else if j > 0 && (i == 0 || lcs[i][j-1] >= lcs[i-1][j]) {
-			lines = append(lines, fmt.Sprintf("+%4d  %s", j, actLines[j-1]))
-			j--
-		} else {
-			lines = append(lines, fmt.Sprintf("-%4d  %s", i, expLines[i-1]))
-			i--
-		}
-	}
+	diff := cfg.diff(expectedStr, content)
+	recordSnapshot(t, name, ref, cfg, "mismatch", expectedStr, content, diff)
+	t.Errorf("snapshot %q mismatch:\n%s%s", name, diff, pendingNote(ref, file))
+}
 
-	// Reverse to get top-to-bottom order.
-	for k := len(lines) - 1; k >= 0; k-- {
-		b.WriteString(lines[k])
-		b.WriteByte('\n')
+// pendingNote writes the actual output next to the golden snapshot for later
+// review and returns a line for the failure message saying where it went.
+func pendingNote(ref goldenRef, content string) string {
+	if err := writePendingSnapshot(ref, content); err != nil {
+		return fmt.Sprintf("\n(cannot write pending snapshot: %v)", err)
 	}
-
-	return b.String()
+	return fmt.Sprintf("\nActual output saved to %s — review with: go run github.com/relux-works/skill-go-testing-tools/tuitestkit/cmd/snapreview", ref.pendingString())
 }
 
-// lcsTable builds the classic LCS (longest common subsequence) DP table.
-func lcsTable(a, b []string) [][]int {
-	m, n := len(a), len(b)
-	table := make([][]int, m+1)
-	for i := range table {
-		table[i] = make([]int, n+1)
+// writeSnapshot writes content to the golden snapshot at ref, creating parent
+// directories as needed.
+func writeSnapshot(t snapshotT, content string, name string, ref goldenRef) {
+	t.Helper()
+	if other := claimSnapshotPath(ref.String(), t.Name()); other != "" {
+		t.Fatalf("snapshot %q: golden file %s is also written by %s in this run\nGive one of the snapshots a different name.", name, ref, other)
 	}
-	for i := 1; i <= m; i++ {
-		for j := 1; j <= n; j++ {
-			if a[i-1] == b[j-1] {
-				table[i][j] = table[i-1][j-1] + 1
-			} else if table[i-1][j] >= table[i][j-1] {
-				table[i][j] = table[i-1][j]
-			} else {
-				table[i][j] = table[i][j-1]
-			}
-		}
+	if err := ref.write(content); err != nil {
+		t.Fatalf("snapshot: cannot write golden file %s: %v", ref, err)
 	}
-	return table
+	clearPendingSnapshot(ref)
 }
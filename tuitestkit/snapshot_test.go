@@ -1,9 +1,11 @@
 package tuitestkit
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -22,15 +24,20 @@ func (m stubModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
 func (m stubModel) View() string                            { return m.view }
 
 // withSnapshotDir sets snapshotBaseDir for the duration of the test and
-// restores the original value afterwards. Also saves/restores UpdateSnapshots.
+// restores the original value afterwards. Also saves/restores the update
+// settings (UpdateSnapshots, UpdateSnapshotsFilter, CreateMissingSnapshots).
 func withSnapshotDir(t *testing.T, dir string) {
 	t.Helper()
 	origBase := snapshotBaseDir
 	origUpdate := UpdateSnapshots
+	origFilter := UpdateSnapshotsFilter
+	origCreate := CreateMissingSnapshots
 	snapshotBaseDir = dir
 	t.Cleanup(func() {
 		snapshotBaseDir = origBase
 		UpdateSnapshots = origUpdate
+		UpdateSnapshotsFilter = origFilter
+		CreateMissingSnapshots = origCreate
 	})
 }
 
@@ -38,6 +45,7 @@ func withSnapshotDir(t *testing.T, dir string) {
 // without aborting the real test. Fatalf panics with a sentinel so the caller
 // can recover and inspect the error message.
 type fakeT struct {
+	name    string
 	failed  bool
 	fataled bool
 	lastErr string
@@ -48,6 +56,8 @@ type fatalSentinel struct{}
 
 func (f *fakeT) Helper() {}
 
+func (f *fakeT) Name() string { return f.name }
+
 func (f *fakeT) Errorf(format string, args ...any) {
 	f.failed = true
 	f.lastErr = fmt.Sprintf(format, args...)
@@ -61,7 +71,7 @@ func (f *fakeT) Fatalf(format string, args ...any) {
 }
 
 // runSnapshot calls snapshot() on fakeT, recovering from Fatalf panics.
-func runSnapshot(ft *fakeT, content, name string, callerSkip int) {
+func runSnapshot(ft *fakeT, content, name string, callerSkip int, opts ...SnapshotOption) {
 	defer func() {
 		if r := recover(); r != nil {
 			if _, ok := r.(fatalSentinel); !ok {
@@ -69,7 +79,7 @@ func runSnapshot(ft *fakeT, content, name string, callerSkip int) {
 			}
 		}
 	}()
-	snapshot(ft, content, name, callerSkip)
+	snapshot(ft, content, name, callerSkip, opts...)
 }
 
 // --- unifiedDiff tests (TASK-260210-2kxauz) ---
@@ -479,3 +489,254 @@ func TestSnapshot_MismatchShowsDiff(t *testing.T) {
 		t.Error("diff should contain added 'CHANGED'")
 	}
 }
+
+// --- Automatic naming tests ---
+
+func TestAutoSnapshotName_Counter(t *testing.T) {
+	first := AutoSnapshotName(t)
+	second := AutoSnapshotName(t)
+	if first != "TestAutoSnapshotName_Counter-1" {
+		t.Errorf("first name = %q", first)
+	}
+	if second != "TestAutoSnapshotName_Counter-2" {
+		t.Errorf("second name = %q", second)
+	}
+}
+
+func TestAutoSnapshotName_Subtests(t *testing.T) {
+	var name, sub string
+	t.Run("empty state", func(t *testing.T) {
+		name = t.Name()
+		sub = AutoSnapshotName(t)
+	})
+	if sub != "TestAutoSnapshotName_Subtests/empty_state-1" {
+		t.Errorf("subtest name = %q", sub)
+	}
+	// The counter is dropped when the subtest finishes, so a rerun
+	// (-count=N) starts again at 1.
+	snapshotCounters.Lock()
+	_, ok := snapshotCounters.n[name]
+	snapshotCounters.Unlock()
+	if ok {
+		t.Error("counter should be cleared after the test finishes")
+	}
+}
+
+func TestSanitizeSnapshotName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"TestBoard", "TestBoard"},
+		{"TestBoard/with_filter", "TestBoard/with_filter"},
+		{"TestBoard/a:b*c?", "TestBoard/a_b_c_"},
+		{"TestBoard/..", "TestBoard/__"},
+		{"TestBoard/ключ", "TestBoard/____"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeSnapshotName(tt.in); got != tt.want {
+			t.Errorf("sanitizeSnapshotName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSnapshotStrAuto_WritesDerivedPath(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	t.Run("list", func(t *testing.T) {
+		SnapshotStrAuto(t, "one")
+		SnapshotStrAuto(t, "\x1b[1mtwo\x1b[0m")
+	})
+
+	for file, want := range map[string]string{
+		"TestSnapshotStrAuto_WritesDerivedPath/list-1.golden": "one",
+		"TestSnapshotStrAuto_WritesDerivedPath/list-2.golden": "two",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("golden %s not written: %v", file, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", file, data, want)
+		}
+	}
+}
+
+func TestSnapshotViewAuto_Model(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	SnapshotViewAuto(t, stubModel{view: "\x1b[32mgreen\x1b[0m"})
+	SnapshotViewRawAuto(t, stubModel{view: "\x1b[32mgreen\x1b[0m"})
+
+	stripped, err := os.ReadFile(filepath.Join(dir, "TestSnapshotViewAuto_Model-1.golden"))
+	if err != nil || string(stripped) != "green" {
+		t.Errorf("stripped golden = %q, %v", stripped, err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "TestSnapshotViewAuto_Model-2.golden"))
+	if err != nil || string(raw) != "\x1b[32mgreen\x1b[0m" {
+		t.Errorf("raw golden = %q, %v", raw, err)
+	}
+}
+
+func TestSnapshot_DuplicateWriterFails(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	first := &fakeT{name: "TestCopyA"}
+	runSnapshot(first, "a", "shared", 1)
+	if first.failed {
+		t.Fatalf("first writer should succeed: %s", first.lastErr)
+	}
+
+	second := &fakeT{name: "TestCopyB"}
+	runSnapshot(second, "b", "shared", 1)
+	if !second.fataled {
+		t.Fatal("second test writing the same golden path should fail")
+	}
+	if !strings.Contains(second.lastErr, "TestCopyA") {
+		t.Errorf("error should name the other test, got: %s", second.lastErr)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "shared.golden"))
+	if err != nil || string(data) != "a" {
+		t.Errorf("golden should keep the first writer's content, got %q, %v", data, err)
+	}
+}
+
+// --- Selective update tests ---
+
+func TestSetUpdateSnapshots(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	tests := []struct {
+		in         string
+		wantUpdate bool
+		wantFilter []string
+	}{
+		{"1", true, nil},
+		{"true", true, nil},
+		{"yes", true, nil},
+		{"ON", true, nil},
+		{"0", false, nil},
+		{"off", false, nil},
+		{"", false, nil},
+		{"board-*", true, []string{"board-*"}},
+		{"board-*, TestList/*", true, []string{"board-*", "TestList/*"}},
+	}
+	for _, tt := range tests {
+		if err := setUpdateSnapshots(tt.in); err != nil {
+			t.Fatalf("setUpdateSnapshots(%q): %v", tt.in, err)
+		}
+		if UpdateSnapshots != tt.wantUpdate || !reflect.DeepEqual(UpdateSnapshotsFilter, tt.wantFilter) {
+			t.Errorf("setUpdateSnapshots(%q) = %v %q, want %v %q", tt.in, UpdateSnapshots, UpdateSnapshotsFilter, tt.wantUpdate, tt.wantFilter)
+		}
+	}
+	if err := setUpdateSnapshots("board-["); err == nil {
+		t.Error("setUpdateSnapshots should reject malformed patterns")
+	}
+}
+
+func TestMatchSnapshotPattern(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"board-*", "board-1", true},
+		{"board-*", "TestX/board-1", true},
+		{"TestX/*", "TestX/board-1", true},
+		{"TestY/*", "TestX/board-1", false},
+		{"Test*", "TestX/board-1", false},
+		{"board-*", "detail", false},
+	} {
+		if got := matchSnapshotPattern(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchSnapshotPattern(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnmatchedUpdatePatterns(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	if err := setUpdateSnapshots("board-*,detial"); err != nil {
+		t.Fatal(err)
+	}
+	if !shouldUpdateSnapshot("TestX/board-1") || shouldUpdateSnapshot("detail") {
+		t.Error("only board-* should select snapshots")
+	}
+	if got := unmatchedUpdatePatterns(); !reflect.DeepEqual(got, []string{"detial"}) {
+		t.Errorf("unmatched = %q, want the misspelt pattern", got)
+	}
+}
+
+func TestUpdateSnapshotsFlag(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(updateSnapshotsFlag{}, "update-snapshots", "")
+
+	if err := fs.Parse([]string{"-update-snapshots"}); err != nil {
+		t.Fatal(err)
+	}
+	if !UpdateSnapshots || UpdateSnapshotsFilter != nil {
+		t.Errorf("bare flag: update = %v, filter = %q", UpdateSnapshots, UpdateSnapshotsFilter)
+	}
+	if err := fs.Parse([]string{"-update-snapshots=board-*"}); err != nil {
+		t.Fatal(err)
+	}
+	if !UpdateSnapshots || !reflect.DeepEqual(UpdateSnapshotsFilter, []string{"board-*"}) {
+		t.Errorf("pattern flag: update = %v, filter = %q", UpdateSnapshots, UpdateSnapshotsFilter)
+	}
+}
+
+func TestSnapshot_UpdateFilterComparesOthers(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	for _, name := range []string{"board-list", "detail"} {
+		if err := os.WriteFile(filepath.Join(dir, name+".golden"), []byte("old"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	UpdateSnapshots = true
+	UpdateSnapshotsFilter = []string{"board-*"}
+
+	SnapshotStr(t, "new", "board-list")
+	data, _ := os.ReadFile(filepath.Join(dir, "board-list.golden"))
+	if string(data) != "new" {
+		t.Errorf("matching snapshot should be updated, got %q", data)
+	}
+
+	ft := &fakeT{}
+	snapshot(ft, "new", "detail", 1)
+	if !ft.failed {
+		t.Error("non-matching snapshot should be compared and fail on drift")
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "detail.golden"))
+	if string(data) != "old" {
+		t.Errorf("non-matching snapshot must not be overwritten, got %q", data)
+	}
+}
+
+func TestSnapshot_CreateMissingOnly(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	if err := os.WriteFile(filepath.Join(dir, "existing.golden"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	UpdateSnapshots = false
+	CreateMissingSnapshots = true
+
+	SnapshotStr(t, "fresh", "missing")
+	data, err := os.ReadFile(filepath.Join(dir, "missing.golden"))
+	if err != nil || string(data) != "fresh" {
+		t.Errorf("missing golden should be created, got %q, %v", data, err)
+	}
+
+	ft := &fakeT{}
+	snapshot(ft, "new", "existing", 1)
+	if !ft.failed {
+		t.Error("existing golden should still be compared")
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "existing.golden"))
+	if string(data) != "old" {
+		t.Errorf("existing golden must not be overwritten, got %q", data)
+	}
+}
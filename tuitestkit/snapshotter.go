@@ -0,0 +1,207 @@
+package tuitestkit
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// UpdateMode selects when a Snapshotter writes golden files.
+type UpdateMode int
+
+const (
+	// UpdateFromEnv follows the package settings: UpdateSnapshots,
+	// UpdateSnapshotsFilter and CreateMissingSnapshots (the default).
+	UpdateFromEnv UpdateMode = iota
+	// UpdateNone only compares, whatever the package settings say.
+	UpdateNone
+	// UpdateMissing writes golden files that do not exist yet and never
+	// overwrites existing ones, as CreateMissingSnapshots.
+	UpdateMissing
+	// UpdateAll overwrites golden files instead of comparing, limited to
+	// names matching Snapshotter.UpdateFilter when it is set.
+	UpdateAll
+)
+
+// ANSIMode selects how a Snapshotter treats escape codes in views.
+type ANSIMode int
+
+const (
+	// ANSIStripped removes escape codes, as SnapshotView (the default).
+	ANSIStripped ANSIMode = iota
+	// ANSIRaw keeps canonicalized escape codes, as SnapshotViewRaw.
+	ANSIRaw
+	// ANSIStyled stores the StyledText format, as SnapshotViewStyled.
+	ANSIStyled
+	// ANSIImage stores SVG images, as SnapshotViewImage.
+	ANSIImage
+)
+
+// header returns the ANSI mode as recorded in golden file headers.
+func (m ANSIMode) header() string {
+	switch m {
+	case ANSIRaw:
+		return ansiRaw
+	case ANSIStyled:
+		return ansiStyled
+	case ANSIImage:
+		return ansiImage
+	}
+	return ansiStripped
+}
+
+// Snapshotter carries snapshot settings for one test (or a group of tests)
+// instead of the package-level variables, so tests with different settings
+// can run with t.Parallel():
+//
+//	snap := &tuitestkit.Snapshotter{
+//	    Dir:       t.TempDir(),
+//	    Update:    tuitestkit.UpdateMissing,
+//	    ANSI:      tuitestkit.ANSIStyled,
+//	    Scrubbers: []tuitestkit.Scrubber{tuitestkit.ScrubRFC3339()},
+//	    Name:      tuitestkit.TestScopedName,
+//	}
+//	snap.SnapshotView(t, model, "board")
+//
+// The zero value behaves like the package-level Snapshot* functions, which
+// delegate to such a default instance. A Snapshotter is not modified by
+// taking snapshots and may be shared by parallel tests; golden files are
+// written atomically under a per-file lock.
+type Snapshotter struct {
+	// Dir is the directory golden files are stored in. Empty means
+	// testdata/snapshots/ next to the calling test file.
+	Dir string
+	// Update selects when golden files are written.
+	Update UpdateMode
+	// UpdateFilter restricts UpdateAll to snapshot names matching one of
+	// these path.Match patterns, as UpdateSnapshotsFilter.
+	UpdateFilter []string
+	// ANSI selects how escape codes in views are stored and compared.
+	ANSI ANSIMode
+	// Storage selects .golden files or txtar archives; unset follows
+	// SnapshotStorage.
+	Storage StorageMode
+	// Scrubbers run on every snapshot, before the options' scrubbers.
+	Scrubbers []Scrubber
+	// Options apply to every snapshot, before the per-call options.
+	Options []SnapshotOption
+	// Name maps the name passed to Snapshot to the golden file name. When
+	// nil, the name is used as given and "" picks AutoSnapshotName(t).
+	Name func(t *testing.T, name string) string
+}
+
+// defaultSnapshotter backs the package-level Snapshot* functions.
+var defaultSnapshotter = &Snapshotter{}
+
+// TestScopedName is a Snapshotter.Name strategy that places snapshots in a
+// directory per test, e.g. "TestBoard/empty_state/list" for "list", so
+// parallel tests can reuse short names without colliding. "" picks
+// AutoSnapshotName(t).
+func TestScopedName(t *testing.T, name string) string {
+	t.Helper()
+	if name == "" {
+		return AutoSnapshotName(t)
+	}
+	return sanitizeSnapshotName(t.Name()) + "/" + name
+}
+
+// SnapshotView captures model.View() and compares (or updates) the golden
+// file named `name` with the Snapshotter's settings.
+func (s *Snapshotter) SnapshotView(t *testing.T, model tea.Model, name string, opts ...SnapshotOption) {
+	t.Helper()
+	s.snapshotView(t, model.View(), s.name(t, name), 3, opts)
+}
+
+// Snapshot is SnapshotView for a pre-rendered view string.
+func (s *Snapshotter) Snapshot(t *testing.T, view string, name string, opts ...SnapshotOption) {
+	t.Helper()
+	s.snapshotView(t, view, s.name(t, name), 3, opts)
+}
+
+// snapshotView renders view in the Snapshotter's ANSI mode and takes the
+// snapshot. callerSkip is as for snapshot.
+func (s *Snapshotter) snapshotView(t snapshotT, view string, name string, callerSkip int, opts []SnapshotOption) {
+	t.Helper()
+	if s.ANSI == ANSIStripped {
+		opts = withStripped(view, opts)
+		view = StripANSI(view)
+	} else {
+		opts = withANSIMode(s.ANSI.header(), opts)
+	}
+	s.snapshotFile(t, view, name, s.ref(name, callerSkip), opts...)
+}
+
+// name applies the naming strategy.
+func (s *Snapshotter) name(t *testing.T, name string) string {
+	t.Helper()
+	switch {
+	case s.Name != nil:
+		return s.Name(t, name)
+	case name == "":
+		return AutoSnapshotName(t)
+	}
+	return name
+}
+
+// options returns the Snapshotter's own options followed by opts.
+func (s *Snapshotter) options(opts []SnapshotOption) []SnapshotOption {
+	all := make([]SnapshotOption, 0, len(s.Options)+len(opts)+1)
+	if len(s.Scrubbers) > 0 {
+		all = append(all, WithScrubbers(s.Scrubbers...))
+	}
+	all = append(all, s.Options...)
+	return append(all, opts...)
+}
+
+// dir returns the golden file directory: Dir, or snapshotDir for the source
+// file callerSkip frames up the stack.
+func (s *Snapshotter) dir(callerSkip int) string {
+	if s.Dir != "" {
+		return s.Dir
+	}
+	return snapshotDir(callerSkip + 1)
+}
+
+// updates reports whether the snapshot called name is overwritten instead of
+// compared, and whether it is written when its golden file is missing.
+func (s *Snapshotter) updates(name string) (overwrite, create bool) {
+	switch s.Update {
+	case UpdateNone:
+		return false, false
+	case UpdateMissing:
+		return false, true
+	case UpdateAll:
+		overwrite = matchSnapshotPatterns(s.UpdateFilter, name)
+		return overwrite, overwrite
+	}
+	return shouldUpdateSnapshot(name), CreateMissingSnapshots
+}
+
+// matchSnapshotPatterns reports whether name matches one of patterns; no
+// patterns match everything.
+func matchSnapshotPatterns(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if matchSnapshotPattern(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSnapshotPattern reports whether name matches the path.Match pattern
+// p, or, for a pattern without a slash, whether its last segment does.
+func matchSnapshotPattern(p, name string) bool {
+	if ok, _ := path.Match(p, name); ok {
+		return true
+	}
+	if strings.Contains(p, "/") {
+		return false
+	}
+	ok, _ := path.Match(p, path.Base(name))
+	return ok
+}
@@ -0,0 +1,137 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotter_ParallelSettings(t *testing.T) {
+	modes := []struct {
+		name string
+		ansi ANSIMode
+		want string
+	}{
+		{"stripped", ANSIStripped, "hi"},
+		{"raw", ANSIRaw, "\x1b[1mhi\x1b[0m"},
+		{"styled", ANSIStyled, "hi\n-- styles --\n[1:0-2 bold]"},
+	}
+	for _, m := range modes {
+		t.Run(m.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			snap := &Snapshotter{Dir: dir, Update: UpdateMissing, ANSI: m.ansi}
+			snap.Snapshot(t, "\x1b[1mhi\x1b[0m", "view")
+
+			data, err := os.ReadFile(filepath.Join(dir, "view.golden"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != m.want {
+				t.Errorf("golden = %q, want %q", data, m.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotter_UpdateModes(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "mode.golden")
+	writeFile(t, golden, "old")
+
+	ft := &fakeT{}
+	(&Snapshotter{Dir: dir, Update: UpdateMissing}).snapshotView(ft, "new", "mode", 1, nil)
+	if !ft.failed {
+		t.Error("UpdateMissing should compare existing golden files")
+	}
+
+	ft = &fakeT{}
+	(&Snapshotter{Dir: dir, Update: UpdateAll, UpdateFilter: []string{"other-*"}}).snapshotView(ft, "new", "mode", 1, nil)
+	if !ft.failed {
+		t.Error("UpdateAll should compare snapshots outside UpdateFilter")
+	}
+
+	ft = &fakeT{}
+	(&Snapshotter{Dir: dir, Update: UpdateAll}).snapshotView(ft, "new", "mode", 1, nil)
+	if ft.failed {
+		t.Fatalf("UpdateAll failed: %s", ft.lastErr)
+	}
+	if data, _ := os.ReadFile(golden); string(data) != "new" {
+		t.Errorf("UpdateAll golden = %q, want new", data)
+	}
+}
+
+func TestSnapshotter_UpdateNoneIgnoresPackageSettings(t *testing.T) {
+	withSnapshotDir(t, t.TempDir())
+	UpdateSnapshots = true
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "keep.golden"), "old")
+
+	ft := &fakeT{}
+	(&Snapshotter{Dir: dir, Update: UpdateNone}).snapshotView(ft, "new", "keep", 1, nil)
+	if !ft.failed {
+		t.Error("UpdateNone should compare even with UpdateSnapshots set")
+	}
+}
+
+func TestSnapshotter_ScrubbersAndOptions(t *testing.T) {
+	dir := t.TempDir()
+	snap := &Snapshotter{
+		Dir:       dir,
+		Update:    UpdateMissing,
+		Scrubbers: []Scrubber{ScrubRegex("id", `id-\d+`, "id-N")},
+		Options:   []SnapshotOption{WithHeader()},
+	}
+	snap.Snapshot(t, "row id-42", "scrubbed")
+
+	data, err := os.ReadFile(filepath.Join(dir, "scrubbed.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "row id-N") || !strings.Contains(string(data), "scrubbers: id") {
+		t.Errorf("golden = %q, want scrubbed content with header", data)
+	}
+}
+
+func TestSnapshotter_Naming(t *testing.T) {
+	dir := t.TempDir()
+	snap := &Snapshotter{Dir: dir, Update: UpdateMissing, Name: TestScopedName}
+	t.Run("sub case", func(t *testing.T) {
+		snap.Snapshot(t, "x", "list")
+	})
+	if !fileExists(filepath.Join(dir, "TestSnapshotter_Naming", "sub_case", "list.golden")) {
+		t.Error("TestScopedName should place the snapshot in a directory per test")
+	}
+
+	auto := &Snapshotter{Dir: dir, Update: UpdateMissing}
+	auto.Snapshot(t, "y", "")
+	if !fileExists(filepath.Join(dir, "TestSnapshotter_Naming-1.golden")) {
+		t.Error("an empty name should use AutoSnapshotName")
+	}
+}
+
+func TestSnapshotter_ConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	snap := &Snapshotter{Dir: dir, Update: UpdateAll}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ft := &fakeT{name: fmt.Sprintf("T%d", i)}
+			snap.snapshotView(ft, strings.Repeat("x", 1000), fmt.Sprintf("c-%d", i), 1, nil)
+			if ft.failed {
+				t.Errorf("write %d failed: %s", i, ft.lastErr)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(files) != 20 {
+		t.Errorf("got %d files, want 20 (no temporary files left): %v", len(files), files)
+	}
+}
@@ -0,0 +1,421 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// SnapshotState serializes v with FormatState and compares (or updates) the
+// golden file named `name`. Use it for state that has no View(), such as
+// reducer state, or to catch state-only regressions (a cursor index, a hidden
+// filter) that the view does not show. A mismatch lists the differing field
+// paths rather than a line diff.
+func SnapshotState(t *testing.T, v any, name string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, FormatState(v), name, 3, withState(opts)...)
+}
+
+// withState prepends the option marking FormatState content.
+func withState(opts []SnapshotOption) []SnapshotOption {
+	return append([]SnapshotOption{func(c *snapshotConfig) { c.state = true }}, opts...)
+}
+
+// FormatState renders v as one "path = value" line per leaf, in a
+// deterministic order:
+//
+//	.cursor = 2
+//	.items[0] = "todo"
+//	.items[1] = "done"
+//	.byID["a"].title = "todo"
+//	.filter = nil
+//	.last.(tuitestkit.keyMsg).key = "enter"
+//
+// Paths follow Go syntax starting at "." for v itself. Unexported fields are
+// included; func, chan and unsafe pointer fields are left out. Map entries are
+// sorted by key. Pointers are followed transparently, except a pointer reached
+// more than once: it is shown as "&N" (numbered in order of appearance) where
+// it is first reached and as "&N" alone after that, so shared and cyclic
+// structures print once with stable IDs instead of addresses. Interface values
+// record their dynamic type as ".(T)". Empty slices, maps and structs print
+// as [], {} and {}; nil ones as nil. time.Time values are formatted as
+// RFC 3339.
+func FormatState(v any) string {
+	f := &stateFormatter{refs: map[stateRef]int{}, ids: map[stateRef]int{}}
+	rv := reflect.ValueOf(v)
+	f.count(rv)
+	f.format(".", rv)
+	return strings.Join(f.lines, "\n")
+}
+
+// stateRef identifies a pointer or map for sharing detection.
+type stateRef struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// stateFormatter holds the state of one FormatState call.
+type stateFormatter struct {
+	refs  map[stateRef]int // times each pointer or map is reached
+	ids   map[stateRef]int // IDs of shared pointers printed so far
+	lines []string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// refOf returns the identity of a non-nil pointer or map.
+func refOf(v reflect.Value) (stateRef, bool) {
+	if (v.Kind() != reflect.Pointer && v.Kind() != reflect.Map) || v.IsNil() {
+		return stateRef{}, false
+	}
+	return stateRef{ptr: v.Pointer(), typ: v.Type()}, true
+}
+
+// count records how often each pointer and map is reached, following each
+// only once.
+func (f *stateFormatter) count(v reflect.Value) {
+	if !v.IsValid() {
+		return
+	}
+	if ref, ok := refOf(v); ok {
+		f.refs[ref]++
+		if f.refs[ref] > 1 {
+			return
+		}
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			f.count(v.Elem())
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f.count(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			f.count(v.Index(i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			f.count(iter.Value())
+		}
+	}
+}
+
+// emit adds one line.
+func (f *stateFormatter) emit(path, value string) {
+	f.lines = append(f.lines, path+" = "+value)
+}
+
+// join appends a child path segment, dropping the root "." before fields and
+// indexes that already start with one.
+func statePath(path, seg string) string {
+	if path == "." {
+		if strings.HasPrefix(seg, ".") {
+			return seg
+		}
+		return "." + seg
+	}
+	return path + seg
+}
+
+// format emits the lines for v at path.
+func (f *stateFormatter) format(path string, v reflect.Value) {
+	if !v.IsValid() {
+		f.emit(path, "nil")
+		return
+	}
+	v = readable(v)
+	if ref, ok := refOf(v); ok && f.refs[ref] > 1 {
+		if id, seen := f.ids[ref]; seen {
+			f.emit(path, fmt.Sprintf("&%d", id))
+			return
+		}
+		id := len(f.ids) + 1
+		f.ids[ref] = id
+		marker := len(f.lines)
+		f.emit(path, fmt.Sprintf("&%d", id))
+		f.formatValue(path, v)
+		// A single value at the same path (a shared *int, an empty map)
+		// joins the marker line, so every path appears once.
+		if len(f.lines) == marker+2 && strings.HasPrefix(f.lines[marker+1], path+" = ") {
+			f.lines[marker] += " " + strings.TrimPrefix(f.lines[marker+1], path+" = ")
+			f.lines = f.lines[:marker+1]
+		}
+		return
+	}
+	f.formatValue(path, v)
+}
+
+// formatValue is format without the sharing check.
+func (f *stateFormatter) formatValue(path string, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		f.emit(path, strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.emit(path, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f.emit(path, strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		f.emit(path, strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+	case reflect.Complex64, reflect.Complex128:
+		f.emit(path, strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits()))
+	case reflect.String:
+		f.emit(path, strconv.Quote(v.String()))
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		// Elided: not state that can be compared meaningfully.
+	case reflect.Pointer:
+		if v.IsNil() {
+			f.emit(path, "nil")
+			return
+		}
+		f.format(path, v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			f.emit(path, "nil")
+			return
+		}
+		f.format(statePath(path, ".("+v.Elem().Type().String()+")"), v.Elem())
+	case reflect.Struct:
+		f.formatStruct(path, v)
+	case reflect.Slice:
+		if v.IsNil() {
+			f.emit(path, "nil")
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			f.emit(path, strconv.Quote(string(v.Bytes())))
+			return
+		}
+		f.formatList(path, v)
+	case reflect.Array:
+		f.formatList(path, v)
+	case reflect.Map:
+		f.formatMap(path, v)
+	}
+}
+
+// readable returns v in a form that can be passed to Interface, so a
+// time.Time behind unexported fields, map entries or interfaces is still
+// formatted as a time: unexported values are re-read through their address,
+// or, for maps, through the map pointer; structs and arrays that are not
+// addressable (map values, interface contents) are copied so their fields
+// are.
+func readable(v reflect.Value) reflect.Value {
+	switch {
+	case v.CanAddr():
+		if !v.CanInterface() {
+			v = reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+		}
+		return v
+	case !v.CanInterface():
+		if v.Kind() != reflect.Map {
+			return v
+		}
+		m := v.UnsafePointer()
+		return reflect.NewAt(v.Type(), unsafe.Pointer(&m)).Elem()
+	case v.Kind() == reflect.Struct || v.Kind() == reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		return c
+	}
+	return v
+}
+
+// formatStruct emits the fields of a struct in declaration order.
+func (f *stateFormatter) formatStruct(path string, v reflect.Value) {
+	if v.Type() == timeType && v.CanInterface() {
+		f.emit(path, v.Interface().(time.Time).Format(time.RFC3339Nano))
+		return
+	}
+	before := len(f.lines)
+	for i := 0; i < v.NumField(); i++ {
+		f.format(statePath(path, "."+v.Type().Field(i).Name), v.Field(i))
+	}
+	if len(f.lines) == before {
+		f.emit(path, "{}")
+	}
+}
+
+// formatList emits the elements of a slice or array.
+func (f *stateFormatter) formatList(path string, v reflect.Value) {
+	if v.Len() == 0 {
+		f.emit(path, "[]")
+		return
+	}
+	for i := 0; i < v.Len(); i++ {
+		f.format(statePath(path, fmt.Sprintf("[%d]", i)), v.Index(i))
+	}
+}
+
+// formatMap emits the entries of a map sorted by key: numerically for
+// numeric keys, by their printed form otherwise.
+func (f *stateFormatter) formatMap(path string, v reflect.Value) {
+	if v.IsNil() {
+		f.emit(path, "nil")
+		return
+	}
+	if v.Len() == 0 {
+		f.emit(path, "{}")
+		return
+	}
+	type entry struct {
+		key   reflect.Value
+		label string
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		entries = append(entries, entry{iter.Key(), stateKey(iter.Key())})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].key, entries[j].key
+		switch {
+		case isIntKind(a.Kind()) && isIntKind(b.Kind()):
+			return a.Int() < b.Int()
+		case isUintKind(a.Kind()) && isUintKind(b.Kind()):
+			return a.Uint() < b.Uint()
+		case isFloatKind(a.Kind()) && isFloatKind(b.Kind()):
+			return a.Float() < b.Float()
+		}
+		return entries[i].label < entries[j].label
+	})
+	for _, e := range entries {
+		f.format(statePath(path, "["+e.label+"]"), v.MapIndex(e.key))
+	}
+}
+
+// stateKey prints a map key on one line.
+func stateKey(k reflect.Value) string {
+	return inlineState(k)
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// inlineState prints v on one line: scalars as in FormatState, errors as
+// error("message"), anything else as its FormatState lines in braces, e.g.
+// {[0] = "-la", [1] = "/tmp"}.
+func inlineState(v reflect.Value) string {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.IsValid() && v.Type().Implements(errorType) && v.CanInterface() {
+		if err, ok := v.Interface().(error); ok && (v.Kind() != reflect.Pointer || !v.IsNil()) {
+			return "error(" + strconv.Quote(err.Error()) + ")"
+		}
+	}
+	sub := &stateFormatter{refs: map[stateRef]int{}, ids: map[stateRef]int{}}
+	sub.count(v)
+	sub.format(".", v)
+	if len(sub.lines) == 1 && strings.HasPrefix(sub.lines[0], ". = ") {
+		return strings.TrimPrefix(sub.lines[0], ". = ")
+	}
+	for i, line := range sub.lines {
+		sub.lines[i] = strings.TrimPrefix(line, ".")
+	}
+	return "{" + strings.Join(sub.lines, ", ") + "}"
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// stateLine is one parsed "path = value" line of FormatState output.
+type stateLine struct {
+	path, value string
+}
+
+// parseState splits FormatState output into lines. Lines that do not have
+// the "path = value" form keep the whole line as their path.
+func parseState(content string) []stateLine {
+	var lines []stateLine
+	for _, line := range strings.Split(content, "\n") {
+		if line == "" {
+			continue
+		}
+		lines = append(lines, splitStateLine(line))
+	}
+	return lines
+}
+
+// splitStateLine splits a line at the first " = " outside a quoted map key.
+func splitStateLine(line string) stateLine {
+	inQuote := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && inQuote:
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case !inQuote && strings.HasPrefix(line[i:], " = "):
+			return stateLine{path: line[:i], value: line[i+3:]}
+		}
+	}
+	return stateLine{path: line}
+}
+
+// maxStateChanges caps the paths listed in one state mismatch message.
+const maxStateChanges = 50
+
+// stateDiff explains a mismatch between two FormatState documents by field
+// path: changed values first, in expected order, then removed and added
+// paths.
+func stateDiff(expected, actual string) string {
+	exp, act := parseState(expected), parseState(actual)
+	actValues := make(map[string]string, len(act))
+	for _, l := range act {
+		actValues[l.path] = l.value
+	}
+	expValues := make(map[string]string, len(exp))
+	var changes []string
+	for _, l := range exp {
+		expValues[l.path] = l.value
+		got, ok := actValues[l.path]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("- %s = %s", l.path, l.value))
+		case got != l.value:
+			changes = append(changes, fmt.Sprintf("~ %s: %s → %s", l.path, l.value, got))
+		}
+	}
+	for _, l := range act {
+		if _, ok := expValues[l.path]; !ok {
+			changes = append(changes, fmt.Sprintf("+ %s = %s", l.path, l.value))
+		}
+	}
+	if len(changes) == 0 {
+		return unifiedDiff(expected, actual)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "state differs at %d path(s) (~ changed, - removed, + added):\n", len(changes))
+	for i, c := range changes {
+		if i == maxStateChanges {
+			fmt.Fprintf(&b, "  ... and %d more\n", len(changes)-i)
+			break
+		}
+		b.WriteString("  " + c + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,188 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stateItem struct {
+	Title string
+	done  bool
+}
+
+type stateNode struct {
+	val  int
+	next *stateNode
+}
+
+type stateModel struct {
+	cursor  int
+	items   []stateItem
+	byID    map[string]*stateItem
+	filter  *string
+	last    any
+	onKey   func()
+	updates chan int
+	created time.Time
+	ratio   float64
+	raw     []byte
+	empty   struct{}
+}
+
+func TestFormatState(t *testing.T) {
+	first := &stateItem{Title: "todo"}
+	m := stateModel{
+		cursor:  1,
+		items:   []stateItem{{Title: "todo"}, {Title: "done", done: true}},
+		byID:    map[string]*stateItem{"b": {Title: "done"}, "a": first},
+		last:    "enter",
+		onKey:   func() {},
+		updates: make(chan int),
+		created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		ratio:   0.5,
+		raw:     []byte("hi"),
+	}
+	got := FormatState(m)
+	want := strings.Join([]string{
+		`.cursor = 1`,
+		`.items[0].Title = "todo"`,
+		`.items[0].done = false`,
+		`.items[1].Title = "done"`,
+		`.items[1].done = true`,
+		`.byID["a"].Title = "todo"`,
+		`.byID["a"].done = false`,
+		`.byID["b"].Title = "done"`,
+		`.byID["b"].done = false`,
+		`.filter = nil`,
+		`.last.(string) = "enter"`,
+		`.created = 2024-01-02T03:04:05Z`,
+		`.ratio = 0.5`,
+		`.raw = "hi"`,
+		`.empty = {}`,
+	}, "\n")
+	if got != want {
+		t.Errorf("FormatState =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatState_Deterministic(t *testing.T) {
+	m := map[int]string{}
+	for i := 0; i < 100; i++ {
+		m[i] = "v"
+	}
+	first := FormatState(m)
+	for i := 0; i < 10; i++ {
+		if FormatState(m) != first {
+			t.Fatal("FormatState output changed between calls")
+		}
+	}
+	if !strings.HasPrefix(first, ".[0] = \"v\"\n.[1] = \"v\"\n.[2] = \"v\"") {
+		t.Errorf("numeric keys should sort numerically, got:\n%s", first[:40])
+	}
+}
+
+func TestFormatState_SharedAndCyclicPointers(t *testing.T) {
+	a := &stateNode{val: 1}
+	b := &stateNode{val: 2, next: a}
+	a.next = b
+	n := 7
+	got := FormatState(struct {
+		head  *stateNode
+		x, y  *int
+		other *stateNode
+	}{head: a, x: &n, y: &n})
+	want := strings.Join([]string{
+		`.head = &1`,
+		`.head.val = 1`,
+		`.head.next.val = 2`,
+		`.head.next.next = &1`,
+		`.x = &2 7`,
+		`.y = &2`,
+		`.other = nil`,
+	}, "\n")
+	if got != want {
+		t.Errorf("FormatState =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatState_TimeBehindUnexportedMap(t *testing.T) {
+	type wrapper struct{ at time.Time }
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 3600))
+	v := struct {
+		m    map[string]time.Time
+		w    map[string]wrapper
+		nest map[string]map[string]time.Time
+		last any
+	}{
+		m:    map[string]time.Time{"a": at},
+		w:    map[string]wrapper{"a": {at}},
+		nest: map[string]map[string]time.Time{"a": {"b": at}},
+		last: wrapper{at},
+	}
+	want := strings.Join([]string{
+		`.m["a"] = 2024-01-02T03:04:05+01:00`,
+		`.w["a"].at = 2024-01-02T03:04:05+01:00`,
+		`.nest["a"]["b"] = 2024-01-02T03:04:05+01:00`,
+		`.last.(tuitestkit.wrapper).at = 2024-01-02T03:04:05+01:00`,
+	}, "\n")
+	if got := FormatState(v); got != want {
+		t.Errorf("FormatState =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatState_Scalars(t *testing.T) {
+	for _, tc := range []struct {
+		v    any
+		want string
+	}{
+		{nil, ". = nil"},
+		{42, ". = 42"},
+		{"a\nb", `. = "a\nb"`},
+		{[]int{}, ". = []"},
+		{[]int(nil), ". = nil"},
+		{map[string]int{}, ". = {}"},
+	} {
+		if got := FormatState(tc.v); got != tc.want {
+			t.Errorf("FormatState(%#v) = %q, want %q", tc.v, got, tc.want)
+		}
+	}
+}
+
+func TestStateDiff(t *testing.T) {
+	exp := ".cursor = 1\n.items[0] = \"a\"\n.items[1] = \"b\"\n.byID[\"x = y\"] = 1"
+	act := ".cursor = 2\n.items[0] = \"a\"\n.byID[\"x = y\"] = 1\n.filter = \"q\""
+	diff := stateDiff(exp, act)
+	for _, want := range []string{
+		"state differs at 3 path(s)",
+		`~ .cursor: 1 → 2`,
+		`- .items[1] = "b"`,
+		`+ .filter = "q"`,
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "x = y") {
+		t.Errorf("unchanged map entry with \" = \" in its key reported:\n%s", diff)
+	}
+}
+
+func TestSnapshotState(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	writeFile(t, filepath.Join(dir, "state.golden"), FormatState(stateItem{Title: "a"}))
+
+	SnapshotState(t, stateItem{Title: "a"}, "state")
+
+	ft := &fakeT{}
+	snapshot(ft, FormatState(stateItem{Title: "a", done: true}), "state", 1, withState(nil)...)
+	if !ft.failed || !strings.Contains(ft.lastErr, "~ .done: false → true") {
+		t.Errorf("state mismatch should show a field-path diff, got: %s", ft.lastErr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "state.golden.new")); err != nil {
+		t.Errorf("state mismatch should leave a pending file: %v", err)
+	}
+}
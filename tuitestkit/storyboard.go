@@ -0,0 +1,206 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Storyboard records the view of a model after each step of an interaction,
+// so a flow can be snapshotted as one golden file of labelled frames instead
+// of unrelated files:
+//
+//	sb := tuitestkit.NewStoryboard(newModel())
+//	sb.Send("after down", tuitestkit.Key("down"))
+//	sb.Send("after enter", tuitestkit.Key("enter"))
+//	sb.ExecCmds("after loading")
+//	sb.Snapshot(t, "select-item")
+//
+// Like Send, Init() is not called and Update must return the model's own type.
+type Storyboard[M tea.Model] struct {
+	model   M
+	pending []tea.Cmd
+	frames  []storyFrame
+}
+
+// storyFrame is one labelled view of a storyboard.
+type storyFrame struct {
+	label string
+	view  string
+}
+
+// NewStoryboard starts a storyboard with the current view of model as its
+// first frame, labelled "initial".
+func NewStoryboard[M tea.Model](model M) *Storyboard[M] {
+	s := &Storyboard[M]{model: model}
+	s.record("initial")
+	return s
+}
+
+// Send sends msgs to the model and records the resulting view as a frame.
+// Cmds returned by Update are kept for ExecCmds.
+func (s *Storyboard[M]) Send(label string, msgs ...tea.Msg) *Storyboard[M] {
+	var cmds []tea.Cmd
+	s.model, cmds = SendAndCollect(s.model, msgs...)
+	s.pending = append(s.pending, cmds...)
+	s.record(label)
+	return s
+}
+
+// ExecCmds runs the Cmds collected since the last ExecCmds, sends their
+// messages to the model (see the package-level ExecCmds) and records the
+// resulting view as a frame.
+func (s *Storyboard[M]) ExecCmds(label string) *Storyboard[M] {
+	msgs := ExecCmds(s.pending...)
+	s.pending = nil
+	return s.Send(label, msgs...)
+}
+
+// Model returns the model after the last step.
+func (s *Storyboard[M]) Model() M {
+	return s.model
+}
+
+// record appends the model's current view as a frame.
+func (s *Storyboard[M]) record(label string) {
+	s.frames = append(s.frames, storyFrame{label: strings.ReplaceAll(label, "\n", " "), view: s.model.View()})
+}
+
+// Snapshot compares (or updates) all frames, ANSI-stripped, against the golden
+// file named `name`. Options apply to each frame on its own, so a MaskRegion
+// row counts from the top of the frame. A mismatch reports the first frame
+// that diverged.
+func (s *Storyboard[M]) Snapshot(t *testing.T, name string, opts ...SnapshotOption) {
+	t.Helper()
+	frames := s.render(StripANSI)
+	snapshot(t, joinFrames(frames), name, 3, withStoryboard(ansiStripped, frames, append([]SnapshotOption{withSource(joinFrames(s.frames))}, opts...))...)
+}
+
+// SnapshotRaw is Snapshot with raw ANSI codes kept, as in SnapshotViewRaw.
+func (s *Storyboard[M]) SnapshotRaw(t *testing.T, name string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, joinFrames(s.frames), name, 3, withStoryboard(ansiRaw, s.frames, opts)...)
+}
+
+// SnapshotStyled is Snapshot in the StyledText format, as in SnapshotViewStyled.
+func (s *Storyboard[M]) SnapshotStyled(t *testing.T, name string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, joinFrames(s.frames), name, 3, withStoryboard(ansiStyled, s.frames, opts)...)
+}
+
+// render returns a copy of the frames with render applied to each view.
+func (s *Storyboard[M]) render(render func(string) string) []storyFrame {
+	frames := make([]storyFrame, len(s.frames))
+	for i, f := range s.frames {
+		f.view = render(f.view)
+		frames[i] = f
+	}
+	return frames
+}
+
+// withStoryboard prepends the options marking storyboard content captured in
+// the given ANSI mode, made of frames.
+func withStoryboard(mode string, frames []storyFrame, opts []SnapshotOption) []SnapshotOption {
+	return withANSIMode(mode, append([]SnapshotOption{withFrames(frames)}, opts...))
+}
+
+// withFrames marks the snapshot as a storyboard of frames, which are prepared
+// one by one rather than split back out of the joined content.
+func withFrames(frames []storyFrame) SnapshotOption {
+	return func(c *snapshotConfig) { c.storyboard, c.frames = true, frames }
+}
+
+// Storyboard golden files separate frames with a numbered, labelled line:
+//
+//	=== frame 1: initial ===
+//	<view>
+//	=== frame 2: after down ===
+//	<view>
+var frameHeaderRe = regexp.MustCompile(`^=== frame (\d+): (.*) ===$`)
+
+// joinFrames renders frames in the storyboard file format.
+func joinFrames(frames []storyFrame) string {
+	var b strings.Builder
+	for i, f := range frames {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "=== frame %d: %s ===\n%s", i+1, f.label, f.view)
+	}
+	return b.String()
+}
+
+// splitFrames parses the storyboard file format. Frame headers must be
+// numbered in sequence, so a view that happens to contain a header-like line
+// is not split. Content before the first header (none in files written by
+// Storyboard) becomes an unlabelled frame so nothing is lost.
+func splitFrames(content string) []storyFrame {
+	var frames []storyFrame
+	var body []string
+	for i, line := range strings.Split(content, "\n") {
+		if m := frameHeaderRe.FindStringSubmatch(line); m != nil && m[1] == strconv.Itoa(len(frames)+1) {
+			if len(frames) > 0 {
+				frames[len(frames)-1].view = strings.Join(body, "\n")
+			}
+			frames = append(frames, storyFrame{label: m[2]})
+			body = nil
+			continue
+		}
+		if i == 0 {
+			frames = append(frames, storyFrame{})
+		}
+		body = append(body, line)
+	}
+	frames[len(frames)-1].view = strings.Join(body, "\n")
+	return frames
+}
+
+// prepareFrames runs prepare on each frame of a storyboard: the frames it was
+// given (see withFrames), or those parsed from content.
+func (c snapshotConfig) prepareFrames(content string) (string, error) {
+	frames := splitFrames(content)
+	if c.frames != nil {
+		frames = append([]storyFrame(nil), c.frames...)
+	}
+	for i := range frames {
+		view, err := c.prepareView(frames[i].view)
+		if err != nil {
+			return "", fmt.Errorf("frame %d (%s): %w", i+1, frames[i].label, err)
+		}
+		frames[i].view = view
+	}
+	return joinFrames(frames), nil
+}
+
+// storyboardDiff explains a storyboard mismatch by its first diverging frame,
+// diffed with frameDiff.
+func storyboardDiff(expected, actual string, frameDiff func(expected, actual string) string) string {
+	exp, act := splitFrames(expected), splitFrames(actual)
+	for i := 0; i < max(len(exp), len(act)); i++ {
+		switch {
+		case i >= len(exp):
+			return fmt.Sprintf("frame %d (%s) is new: expected %d frames, got %d\n", i+1, act[i].label, len(exp), len(act))
+		case i >= len(act):
+			return fmt.Sprintf("frame %d (%s) is missing: expected %d frames, got %d\n", i+1, exp[i].label, len(exp), len(act))
+		case exp[i].label != act[i].label:
+			return fmt.Sprintf("frame %d label changed: %q → %q\n", i+1, exp[i].label, act[i].label)
+		case exp[i].view != act[i].view:
+			var later int
+			for j := i + 1; j < min(len(exp), len(act)); j++ {
+				if exp[j] != act[j] {
+					later++
+				}
+			}
+			msg := fmt.Sprintf("first diverging frame: %d (%s)", i+1, act[i].label)
+			if later > 0 {
+				msg += fmt.Sprintf(", %d later frame(s) also differ", later)
+			}
+			return msg + "\n" + frameDiff(exp[i].view, act[i].view)
+		}
+	}
+	return frameDiff(expected, actual)
+}
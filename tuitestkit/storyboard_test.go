@@ -0,0 +1,154 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func loadCmd() tea.Cmd {
+	return func() tea.Msg { return incMsg{} }
+}
+
+func TestStoryboard_RecordsFrames(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	sb := NewStoryboard(counterModel{})
+	sb.Send("after inc", incMsg{}).
+		Send("after load", cmdMsg{cmd: loadCmd()}).
+		ExecCmds("after loading")
+	sb.Snapshot(t, "story")
+
+	if sb.Model().count != 2 {
+		t.Errorf("count = %d, want 2", sb.Model().count)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "story.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"=== frame 1: initial ===",
+		"count: 0",
+		"=== frame 2: after inc ===",
+		"count: 1",
+		"=== frame 3: after load ===",
+		"count: 1",
+		"=== frame 4: after loading ===",
+		"count: 2",
+	}, "\n")
+	if string(data) != want {
+		t.Errorf("golden =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestStoryboard_ReportsFirstDivergingFrame(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	writeFile(t, filepath.Join(dir, "flow.golden"), joinFrames([]storyFrame{
+		{"initial", "count: 0"},
+		{"after inc", "count: 1"},
+		{"after inc", "count: 2"},
+	}))
+
+	sb := NewStoryboard(counterModel{}).Send("after inc", incMsg{}).Send("after inc", incMsg{}, incMsg{})
+	ft := &fakeT{}
+	frames := sb.render(StripANSI)
+	snapshot(ft, joinFrames(frames), "flow", 1, withStoryboard(ansiStripped, frames, nil)...)
+	if !strings.Contains(ft.lastErr, "first diverging frame: 3 (after inc)\n") {
+		t.Errorf("mismatch should name the diverging frame: %s", ft.lastErr)
+	}
+	if !strings.Contains(ft.lastErr, "-   1  count: 2") || !strings.Contains(ft.lastErr, "+   1  count: 3") {
+		t.Errorf("mismatch should diff the frame: %s", ft.lastErr)
+	}
+}
+
+func TestStoryboardDiff(t *testing.T) {
+	frames := func(fs ...storyFrame) string { return joinFrames(fs) }
+	tests := []struct {
+		name     string
+		exp, act string
+		want     string
+	}{
+		{"new frame", frames(storyFrame{"initial", "a"}), frames(storyFrame{"initial", "a"}, storyFrame{"after x", "b"}), "frame 2 (after x) is new: expected 1 frames, got 2"},
+		{"missing frame", frames(storyFrame{"initial", "a"}, storyFrame{"after x", "b"}), frames(storyFrame{"initial", "a"}), "frame 2 (after x) is missing"},
+		{"label", frames(storyFrame{"initial", "a"}, storyFrame{"after x", "b"}), frames(storyFrame{"initial", "a"}, storyFrame{"after y", "b"}), `frame 2 label changed: "after x" → "after y"`},
+		{"later frames", frames(storyFrame{"s1", "a"}, storyFrame{"s2", "b"}, storyFrame{"s3", "c"}), frames(storyFrame{"s1", "A"}, storyFrame{"s2", "b"}, storyFrame{"s3", "C"}), "first diverging frame: 1 (s1), 1 later frame(s) also differ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := storyboardDiff(tt.exp, tt.act, unifiedDiff); !strings.Contains(got, tt.want) {
+				t.Errorf("storyboardDiff =\n%s\nwant it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitFrames(t *testing.T) {
+	content := "=== frame 1: initial ===\nline\n=== frame 5: not a header ===\n=== frame 2: next ===\n"
+	got := splitFrames(content)
+	want := []storyFrame{
+		{"initial", "line\n=== frame 5: not a header ==="},
+		{"next", ""},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("splitFrames = %+v, want %+v", got, want)
+	}
+	if joinFrames(got) != content {
+		t.Errorf("round trip = %q", joinFrames(got))
+	}
+
+	legacy := splitFrames("no headers\nat all")
+	if len(legacy) != 1 || legacy[0].view != "no headers\nat all" {
+		t.Errorf("content without headers should be one frame: %+v", legacy)
+	}
+}
+
+func TestStoryboard_OptionsApplyPerFrame(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	sb := NewStoryboard(counterModel{}).Send("after inc", incMsg{})
+	sb.Snapshot(t, "masked-story", MaskRegion(Region{Row: 0, Col: 7, Width: 1, Height: 1}))
+
+	data, _ := os.ReadFile(filepath.Join(dir, "masked-story.golden"))
+	_, content, _ := parseSnapshotHeader(string(data))
+	if content != "=== frame 1: initial ===\ncount: #\n=== frame 2: after inc ===\ncount: #" {
+		t.Errorf("mask should apply to every frame:\n%s", content)
+	}
+}
+
+func TestStoryboard_HeaderLikeLineInView(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	view := "log:\n=== frame 2: fake ===\ncount: 0"
+	sb := NewStoryboard(stubModel{view: view})
+	sb.Snapshot(t, "tricky-story", MaskRegion(Region{Row: 2, Col: 7, Width: 1, Height: 1}))
+
+	data, _ := os.ReadFile(filepath.Join(dir, "tricky-story.golden"))
+	_, content, _ := parseSnapshotHeader(string(data))
+	if want := "=== frame 1: initial ===\nlog:\n=== frame 2: fake ===\ncount: #"; content != want {
+		t.Errorf("the view should be prepared as one frame:\n%s\nwant\n%s", content, want)
+	}
+}
+
+func TestStoryboard_Styled(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+
+	sb := NewStoryboard(stubModel{view: "\x1b[1mhi\x1b[0m"})
+	sb.SnapshotStyled(t, "styled-story")
+
+	data, _ := os.ReadFile(filepath.Join(dir, "styled-story.golden"))
+	if string(data) != "=== frame 1: initial ===\nhi\n-- styles --\n[1:0-2 bold]" {
+		t.Errorf("golden = %q", data)
+	}
+}
@@ -0,0 +1,221 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// styledSeparator divides the text layer of a styled snapshot from its
+// style layer.
+const styledSeparator = "-- styles --"
+
+// ansiStyled is the ANSI mode of styled snapshots.
+const ansiStyled = "styled"
+
+// StyledText renders a view with ANSI escape codes as readable text: the
+// plain text, then (if anything is styled) a style layer with one run of
+// identically styled cells per line:
+//
+//	Todo  Done
+//	-- styles --
+//	[1:0-4 bold fg=#ff0000]
+//	[1:6-10 faint]
+//
+// Runs read "[line:col-end attributes]", with 1-based line numbers as in
+// diffs and 0-based, end-exclusive cell columns as in Locate. Attributes are
+// bold, faint, italic, underline, blink, reverse, hidden and strike; colors
+// are fg=/bg=/ul= (underline color) with a basic ANSI color name or
+// "#rrggbb". Equivalent escape sequences (reordered SGR parameters,
+// 256-palette vs 24-bit encodings of the same color) produce identical output.
+func StyledText(view string) string {
+	return formatStyled(parseStyledScreen(view))
+}
+
+// formatStyled renders scr in the StyledText format.
+func formatStyled(scr styledScreen) string {
+	var b strings.Builder
+	var runs []string
+	for row, cells := range scr.rows {
+		if row > 0 {
+			b.WriteByte('\n')
+		}
+		for _, c := range cells {
+			b.WriteString(c.text)
+		}
+		for col := 0; col < len(cells); {
+			end := col + 1
+			for end < len(cells) && cells[end].style == cells[col].style {
+				end++
+			}
+			if st := cells[col].style; !st.isPlain() {
+				runs = append(runs, fmt.Sprintf("[%d:%d-%d %s]", row+1, col, end, st))
+			}
+			col = end
+		}
+	}
+	if len(runs) > 0 {
+		b.WriteString("\n" + styledSeparator + "\n")
+		b.WriteString(strings.Join(runs, "\n"))
+	}
+	return b.String()
+}
+
+var styledRunRe = regexp.MustCompile(`^\[(\d+):(\d+)-(\d+) (.+)\]$`)
+
+// parseStyled reads content in the StyledText format back into cells.
+// Content without a valid style layer is all text.
+func parseStyled(content string) styledScreen {
+	text, layer := content, ""
+	if i := strings.LastIndex(content, "\n"+styledSeparator+"\n"); i >= 0 {
+		text, layer = content[:i], content[i+len(styledSeparator)+2:]
+	}
+	styled := parseStyledScreen(StripANSI(text))
+	if layer == "" {
+		return styled
+	}
+	for _, line := range strings.Split(layer, "\n") {
+		m := styledRunRe.FindStringSubmatch(line)
+		if m == nil {
+			return parseStyledScreen(StripANSI(content))
+		}
+		row, _ := strconv.Atoi(m[1])
+		col0, _ := strconv.Atoi(m[2])
+		col1, _ := strconv.Atoi(m[3])
+		st, err := parseCellStyle(m[4])
+		if err != nil {
+			return parseStyledScreen(StripANSI(content))
+		}
+		if row < 1 || row > len(styled.rows) {
+			continue
+		}
+		cells := styled.rows[row-1]
+		for c := max(col0, 0); c < col1 && c < len(cells); c++ {
+			cells[c].style = st
+		}
+	}
+	return styled
+}
+
+// plain returns the text layer of scr.
+func (scr styledScreen) plain() string {
+	lines := make([]string, len(scr.rows))
+	for i := range scr.rows {
+		lines[i] = scr.text(i)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// styledDiff explains a mismatch between two StyledText documents.
+func styledDiff(expected, actual string) string {
+	return screenDiff(parseStyled(expected), parseStyled(actual))
+}
+
+// rawDiff explains a mismatch between two raw (escape-coded) views by their
+// cells rather than their bytes. Views that differ only in escape sequences
+// without a visible effect (cursor movement, hyperlinks) fall back to a
+// byte diff.
+func rawDiff(expected, actual string) string {
+	if diff := screenDiff(parseStyledScreen(expected), parseStyledScreen(actual)); diff != "" {
+		return diff
+	}
+	return "text and styles identical, escape sequences differ:\n" + unifiedDiff(expected, actual)
+}
+
+// maxStyleChanges caps the style changes listed in one mismatch message.
+const maxStyleChanges = 20
+
+// screenDiff reports text and style changes separately: a line diff of the
+// text when it changed, then the style changes on lines whose text is
+// unchanged, e.g. "line 2 cols 3-10: text identical, bold → faint". An empty
+// result means the screens look the same.
+func screenDiff(exp, act styledScreen) string {
+	var b strings.Builder
+	expText, actText := exp.plain(), act.plain()
+	lines := DiffLines(expText, actText)
+	if expText != actText {
+		b.WriteString("text changes:\n")
+		b.WriteString(unifiedDiff(expText, actText))
+	}
+	if changes := styleChanges(exp, act, lines); len(changes) > 0 {
+		b.WriteString("style changes:\n")
+		for i, c := range changes {
+			if i == maxStyleChanges {
+				fmt.Fprintf(&b, "  ... and %d more\n", len(changes)-i)
+				break
+			}
+			b.WriteString("  " + c + "\n")
+		}
+	}
+	return b.String()
+}
+
+// styleChanges lists the style differences between lines that diff as equal,
+// grouping adjacent cells with the same change.
+func styleChanges(exp, act styledScreen, lines []DiffLine) []string {
+	var changes []string
+	for _, l := range lines {
+		if l.Op != DiffEqual {
+			continue
+		}
+		er, ar := l.OldLine-1, l.NewLine-1
+		width := max(len(exp.rows[er]), len(act.rows[ar]))
+		for col := 0; col < width; {
+			o, n := exp.styleAt(er, col), act.styleAt(ar, col)
+			end := col + 1
+			for end < width && exp.styleAt(er, end) == o && act.styleAt(ar, end) == n {
+				end++
+			}
+			if o != n {
+				changes = append(changes, fmt.Sprintf("line %d cols %d-%d: text identical, %s", l.NewLine, col, end, describeStyleChange(o, n)))
+			}
+			col = end
+		}
+	}
+	return changes
+}
+
+// describeStyleChange names what differs between two styles, e.g.
+// "bold → faint, fg changed #888888 → #ffffff".
+func describeStyleChange(o, n cellStyle) string {
+	var parts []string
+	if o.attrs != n.attrs {
+		parts = append(parts, o.attrString()+" → "+n.attrString())
+	}
+	color := func(c string) string {
+		if c == "" {
+			return "default"
+		}
+		return c
+	}
+	if o.fg != n.fg {
+		parts = append(parts, "fg changed "+color(o.fg)+" → "+color(n.fg))
+	}
+	if o.bg != n.bg {
+		parts = append(parts, "bg changed "+color(o.bg)+" → "+color(n.bg))
+	}
+	if o.ul != n.ul {
+		parts = append(parts, "underline color changed "+color(o.ul)+" → "+color(n.ul))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SnapshotViewStyled captures model.View() in the StyledText format and
+// compares (or updates) the golden file named `name`. Unlike SnapshotViewRaw
+// the golden file is readable in code review, and a mismatch reports style
+// changes by position ("line 2 cols 3-10: bold → faint") instead of escape
+// code bytes.
+func SnapshotViewStyled(t *testing.T, model tea.Model, name string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, model.View(), name, 3, withANSIMode(ansiStyled, opts)...)
+}
+
+// SnapshotStrStyled is SnapshotViewStyled for a pre-rendered view string.
+func SnapshotStrStyled(t *testing.T, view string, name string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, view, name, 3, withANSIMode(ansiStyled, opts)...)
+}
@@ -0,0 +1,179 @@
+package tuitestkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStyledText(t *testing.T) {
+	view := "\x1b[1;38;2;255;0;0mTodo\x1b[0m  \x1b[2mDone\x1b[0m\nplain"
+	want := strings.Join([]string{
+		"Todo  Done",
+		"plain",
+		"-- styles --",
+		"[1:0-4 bold fg=#ff0000]",
+		"[1:6-10 faint]",
+	}, "\n")
+	if got := StyledText(view); got != want {
+		t.Errorf("StyledText =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestStyledText_EquivalentEncodings(t *testing.T) {
+	a := StyledText("\x1b[1;38;5;196mx\x1b[0m\x1b[0m")
+	b := StyledText("\x1b[38;2;255;0;0m\x1b[1mx\x1b[m")
+	if a != b {
+		t.Errorf("equivalent SGR should render the same:\n%s\n---\n%s", a, b)
+	}
+}
+
+func TestStyledText_PlainHasNoStyleLayer(t *testing.T) {
+	if got := StyledText("just text\n"); got != "just text\n" {
+		t.Errorf("StyledText = %q", got)
+	}
+}
+
+func TestParseStyled_RoundTrip(t *testing.T) {
+	view := "a\x1b[4m世\x1b[0mb\n\x1b[44m  \x1b[0m"
+	doc := StyledText(view)
+	if got := formatStyled(parseStyled(doc)); got != doc {
+		t.Errorf("round trip =\n%s\nwant\n%s", got, doc)
+	}
+}
+
+func TestParseStyled_InvalidLayerIsText(t *testing.T) {
+	doc := "text\n-- styles --\nnot a run"
+	if got := parseStyled(doc).plain(); got != doc {
+		t.Errorf("plain = %q, want the whole document", got)
+	}
+}
+
+func TestStyledDiff_StyleOnly(t *testing.T) {
+	expected := StyledText("ok \x1b[1mSaving...\x1b[0m\nfooter")
+	actual := StyledText("ok \x1b[2mSaving...\x1b[0m\nfooter")
+	got := styledDiff(expected, actual)
+	if strings.Contains(got, "--- expected") {
+		t.Errorf("identical text should not print a line diff:\n%s", got)
+	}
+	if !strings.Contains(got, "line 1 cols 3-12: text identical, bold → faint") {
+		t.Errorf("diff should describe the style change:\n%s", got)
+	}
+}
+
+func TestStyledDiff_Colors(t *testing.T) {
+	expected := StyledText("\x1b[38;5;102mab\x1b[0mc")
+	actual := StyledText("\x1b[97;41mab\x1b[0m\x1b[32mc\x1b[0m")
+	got := styledDiff(expected, actual)
+	for _, want := range []string{
+		"line 1 cols 0-2: text identical, fg changed #878787 → bright-white, bg changed default → red",
+		"line 1 cols 2-3: text identical, fg changed default → green",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diff missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestStyledDiff_TextAndStyle(t *testing.T) {
+	expected := StyledText("title\n\x1b[1mbody\x1b[0m")
+	actual := StyledText("renamed\nbody")
+	got := styledDiff(expected, actual)
+	if !strings.Contains(got, "-   1  title") || !strings.Contains(got, "+   1  renamed") {
+		t.Errorf("text change should be diffed by line:\n%s", got)
+	}
+	if !strings.Contains(got, "line 2 cols 0-4: text identical, bold → plain") {
+		t.Errorf("style change on an unchanged line should be listed:\n%s", got)
+	}
+}
+
+func TestSnapshotStrStyled(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+	SnapshotStrStyled(t, "\x1b[1mTitle\x1b[0m", "styled")
+
+	data, err := os.ReadFile(filepath.Join(dir, "styled.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "Title\n-- styles --\n[1:0-5 bold]" {
+		t.Errorf("golden = %q", data)
+	}
+
+	UpdateSnapshots = false
+	ft := &fakeT{}
+	snapshot(ft, "\x1b[1m\x1b[3mTitle\x1b[0m", "styled", 1, withANSIMode(ansiStyled, nil)...)
+	if !strings.Contains(ft.lastErr, "line 1 cols 0-5: text identical, bold → bold italic") {
+		t.Errorf("expected a style change explanation, got: %s", ft.lastErr)
+	}
+}
+
+func TestSnapshotViewStyled_Masked(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	UpdateSnapshots = true
+	model := stubModel{view: "\x1b[32mup 12:04\x1b[0m"}
+	SnapshotViewStyled(t, model, "masked-styled", MaskAfter("up ", 5))
+
+	data, _ := os.ReadFile(filepath.Join(dir, "masked-styled.golden"))
+	// The mask replaces cells with unstyled fill, so masked styles cannot
+	// flap either.
+	if !strings.HasSuffix(string(data), "up #####\n-- styles --\n[1:0-3 fg=green]") {
+		t.Errorf("golden = %q", data)
+	}
+}
+
+func TestRawDiff_StyleOnly(t *testing.T) {
+	expected := "header\n\x1b[38;5;102mno tasks\x1b[0m"
+	actual := "header\n\x1b[97mno tasks\x1b[0m"
+	got := rawDiff(expected, actual)
+	if strings.Contains(got, "text changes") {
+		t.Errorf("style-only change should not report text changes:\n%s", got)
+	}
+	if !strings.Contains(got, "line 2 cols 0-8: text identical, fg changed #878787 → bright-white") {
+		t.Errorf("diff should describe the style change:\n%s", got)
+	}
+}
+
+func TestRawDiff_TextChange(t *testing.T) {
+	got := rawDiff("\x1b[1mTasks (3)\x1b[0m", "\x1b[1mTasks (4)\x1b[0m")
+	if !strings.Contains(got, "text changes:\n") || !strings.Contains(got, "+   1  Tasks (4)") {
+		t.Errorf("text change should be diffed without escape codes:\n%s", got)
+	}
+	if strings.Contains(got, "\x1b") || strings.Contains(got, "style changes") {
+		t.Errorf("unexpected escape codes or style changes:\n%s", got)
+	}
+}
+
+func TestRawDiff_InvisibleEscapes(t *testing.T) {
+	got := rawDiff("a\x1b[2Kb", "ab")
+	if !strings.HasPrefix(got, "text and styles identical, escape sequences differ:\n") {
+		t.Errorf("diff = %s", got)
+	}
+}
+
+func TestScreenDiff_CapsStyleChanges(t *testing.T) {
+	var exp, act []string
+	for i := 0; i < maxStyleChanges+5; i++ {
+		exp = append(exp, "row")
+		act = append(act, "\x1b[1mrow\x1b[0m")
+	}
+	got := rawDiff(strings.Join(exp, "\n"), strings.Join(act, "\n"))
+	if !strings.Contains(got, "  ... and 5 more\n") {
+		t.Errorf("long style change lists should be capped:\n%s", got)
+	}
+}
+
+func TestSnapshotViewRaw_StyleMismatchExplained(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	writeFile(t, filepath.Join(dir, "raw-style.golden"), "\x1b[2mSaving\x1b[0m")
+
+	ft := &fakeT{}
+	snapshot(ft, "\x1b[1mSaving\x1b[0m", "raw-style", 1, withANSIMode(ansiRaw, nil)...)
+	if !strings.Contains(ft.lastErr, "line 1 cols 0-6: text identical, faint → bold") {
+		t.Errorf("raw mismatch should be explained by cells: %s", ft.lastErr)
+	}
+}
@@ -0,0 +1,393 @@
+package tuitestkit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Table is a rendered table parsed back into header and cell text.
+// All cells are trimmed of surrounding whitespace.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// TableRow is a single table row keyed by header name.
+type TableRow map[string]string
+
+// Column returns the index of the column whose header equals name, or -1.
+func (tb Table) Column(name string) int {
+	for i, h := range tb.Header {
+		if h == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Cell returns the cell at data row `row` (zero-based, header excluded) in the
+// column named `column`. The second result is false if either is missing.
+func (tb Table) Cell(row int, column string) (string, bool) {
+	col := tb.Column(column)
+	if col < 0 || row < 0 || row >= len(tb.Rows) || col >= len(tb.Rows[row]) {
+		return "", false
+	}
+	return tb.Rows[row][col], true
+}
+
+// Row returns data row `row` keyed by header name.
+func (tb Table) Row(row int) TableRow {
+	out := TableRow{}
+	if row < 0 || row >= len(tb.Rows) {
+		return out
+	}
+	for i, h := range tb.Header {
+		if i < len(tb.Rows[row]) {
+			out[h] = tb.Rows[row][i]
+		}
+	}
+	return out
+}
+
+// Cells returns the header followed by all data rows.
+func (tb Table) Cells() [][]string {
+	out := make([][]string, 0, len(tb.Rows)+1)
+	out = append(out, tb.Header)
+	return append(out, tb.Rows...)
+}
+
+// errNoTable is returned by ParseTable when the view holds no table.
+var errNoTable = errors.New("no table found in view")
+
+// ViewTable parses the table rendered by model.View(). See ParseTable.
+func ViewTable(model tea.Model) (Table, error) {
+	return ParseTable(model.View())
+}
+
+// ParseTable parses a rendered table into header and rows.
+//
+// Bordered tables (lipgloss/table, or any grid drawn with box runes) are found
+// by their top-left corner; columns come from the ┬ junctions in the top
+// border and rule lines such as ├───┼───┤ are skipped. A header separator
+// crossing every column is required, so panes sharing borders side by side
+// (┌──┬──┐ over ├──┴──┤) are not mistaken for a table.
+//
+// Otherwise the view is treated as a borderless table (bubbles/table style):
+// the header is the first non-blank line, or the line above the first rule
+// line (────), and rows continue until the next blank line. Columns are
+// separated by runs of cells that are blank on every row, which keeps left-
+// and right-aligned columns intact; when header labels are set two or more
+// spaces apart, runs under one label ("Last Updated") stay one column.
+//
+// To parse a table that shares the screen with other content, pass the
+// content of its pane, e.g. ParseTable(box.Content()).
+func ParseTable(view string) (Table, error) {
+	g := newCellGrid(view)
+	if tb, ok := g.borderedTable(); ok {
+		return tb, nil
+	}
+	return g.borderlessTable()
+}
+
+// borderedTable parses the first box-drawn table in the grid.
+func (g cellGrid) borderedTable() (Table, bool) {
+	for top := 0; top < g.height(); top++ {
+		left := firstNonSpace(g.rows[top])
+		if left < 0 || !g.connects(top, left, connDown|connRight) {
+			continue
+		}
+
+		seps := []int{left}
+		for col := left + 1; col < len(g.rows[top]); col++ {
+			if g.connects(top, col, connDown) {
+				seps = append(seps, col)
+			}
+			if !g.connects(top, col, connLeft|connRight) {
+				break
+			}
+		}
+		// A single column is just a box (e.g. a pane), not a table; panes
+		// sharing borders side by side have no header separator.
+		if len(seps) < 3 || !g.hasColumnRule(top, seps) {
+			continue
+		}
+
+		var tb Table
+		for row := top + 1; row < g.height(); row++ {
+			if g.connects(row, left, connUp|connRight) && !g.connects(row, left, connDown) {
+				break // bottom border
+			}
+			if g.ruleLine(row) {
+				continue
+			}
+			cells := make([]string, len(seps)-1)
+			for i := range cells {
+				cells[i] = strings.TrimSpace(g.text(row, seps[i]+1, seps[i+1]))
+			}
+			if tb.Header == nil {
+				tb.Header = cells
+			} else {
+				tb.Rows = append(tb.Rows, cells)
+			}
+		}
+		if tb.Header != nil {
+			return tb, true
+		}
+	}
+	return Table{}, false
+}
+
+// borderlessTable parses the grid as a whitespace-aligned table.
+func (g cellGrid) borderlessTable() (Table, error) {
+	start := -1
+	for row := 0; row < g.height(); row++ {
+		if g.ruleLine(row) && row > 0 && !g.blankLine(row-1) {
+			start = row - 1
+			break
+		}
+	}
+	if start < 0 {
+		for row := 0; row < g.height(); row++ {
+			if !g.blankLine(row) {
+				start = row
+				break
+			}
+		}
+	}
+	if start < 0 {
+		return Table{}, errNoTable
+	}
+
+	var rows []int
+	width := 0
+	for row := start; row < g.height(); row++ {
+		if g.blankLine(row) {
+			break
+		}
+		if g.ruleLine(row) {
+			continue
+		}
+		rows = append(rows, row)
+		width = max(width, len(g.rows[row]))
+	}
+
+	// A column boundary is any cell that is blank on every row.
+	var spans [][2]int
+	colStart := -1
+	for col := 0; col <= width; col++ {
+		blank := col == width
+		if !blank {
+			blank = true
+			for _, row := range rows {
+				if c := g.at(row, col); c != "" && c != " " {
+					blank = false
+					break
+				}
+			}
+		}
+		switch {
+		case !blank && colStart < 0:
+			colStart = col
+		case blank && colStart >= 0:
+			spans = append(spans, [2]int{colStart, col})
+			colStart = -1
+		}
+	}
+
+	spans = mergeByHeader(spans, g.headerTokens(rows[0]))
+
+	var tb Table
+	for i, row := range rows {
+		cells := make([]string, len(spans))
+		for j, s := range spans {
+			cells[j] = strings.TrimSpace(g.text(row, s[0], s[1]))
+		}
+		if i == 0 {
+			tb.Header = cells
+		} else {
+			tb.Rows = append(tb.Rows, cells)
+		}
+	}
+	return tb, nil
+}
+
+// headerTokens returns the column ranges of the header labels in row: text
+// separated by two or more spaces, so a label may contain single spaces.
+func (g cellGrid) headerTokens(row int) [][2]int {
+	var tokens [][2]int
+	start, gap := -1, 0
+	for col, c := range g.rows[row] {
+		if c == " " {
+			gap++
+			if start >= 0 && gap == 2 {
+				tokens = append(tokens, [2]int{start, col - 1})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = col
+		}
+		gap = 0
+	}
+	if start >= 0 {
+		tokens = append(tokens, [2]int{start, len(g.rows[row]) - gap})
+	}
+	return tokens
+}
+
+// mergeByHeader joins neighbouring gutter-separated spans that lie under the
+// same header label, so "Last Updated" over short values stays one column.
+// A header without two-space gaps says nothing about where its columns start;
+// the gutters are used as they are.
+func mergeByHeader(spans, tokens [][2]int) [][2]int {
+	if len(tokens) < 2 {
+		return spans
+	}
+	overlaps := func(s, tok [2]int) bool { return s[0] < tok[1] && tok[0] < s[1] }
+	merged := [][2]int{spans[0]}
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		joined := false
+		for _, tok := range tokens {
+			if overlaps(*last, tok) && overlaps(s, tok) {
+				joined = true
+				break
+			}
+		}
+		if joined {
+			last[1] = s[1]
+		} else {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+// hasColumnRule reports whether a rule line (├──┼──┤) crossing every column
+// boundary in seps follows the top border at row top, before the box closes.
+func (g cellGrid) hasColumnRule(top int, seps []int) bool {
+	left, right := seps[0], seps[len(seps)-1]
+	for row := top + 1; row < g.height(); row++ {
+		if !g.connects(row, left, connUp|connDown) {
+			return false // bottom border or no box
+		}
+		if !g.connects(row, left, connRight) || !g.connects(row, right, connUp|connDown|connLeft) {
+			continue
+		}
+		crosses := true
+		for _, col := range seps[1 : len(seps)-1] {
+			if !g.connects(row, col, connUp|connDown|connLeft|connRight) {
+				crosses = false
+				break
+			}
+		}
+		if crosses {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleLine reports whether row consists only of box-drawing runes and spaces,
+// with at least one box rune.
+func (g cellGrid) ruleLine(row int) bool {
+	seen := false
+	for col, c := range g.rows[row] {
+		switch {
+		case c == " " || c == "":
+		case g.isBoxRune(row, col):
+			seen = true
+		default:
+			return false
+		}
+	}
+	return seen
+}
+
+// blankLine reports whether row holds only spaces.
+func (g cellGrid) blankLine(row int) bool {
+	return firstNonSpace(g.rows[row]) < 0
+}
+
+// firstNonSpace returns the index of the first non-blank cell, or -1.
+func firstNonSpace(cells []string) int {
+	for i, c := range cells {
+		if c != " " && c != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// TableCell returns the cell at data row `row` (zero-based, header excluded)
+// in the column named `column` of the table in model.View(). Fails the test
+// immediately if there is no table, no such column, or no such row.
+func TableCell(t testing.TB, model tea.Model, row int, column string) string {
+	t.Helper()
+	tb := mustViewTable(t, model, "TableCell")
+	if tb.Column(column) < 0 {
+		fatalView(t, model.View(), "TableCell: no column %q (header: %q)", column, tb.Header)
+		return ""
+	}
+	cell, ok := tb.Cell(row, column)
+	if !ok {
+		fatalView(t, model.View(), "TableCell: row %d out of range (table has %d rows)", row, len(tb.Rows))
+	}
+	return cell
+}
+
+// AssertTableCell asserts that the cell at data row `row` in the column named
+// `column` equals want.
+func AssertTableCell(t testing.TB, model tea.Model, row int, column string, want string) {
+	t.Helper()
+	if got := TableCell(t, model, row, column); got != want {
+		failView(t, model.View(), "AssertTableCell: row %d, column %q = %q, want %q", row, column, got, want)
+	}
+}
+
+// FindTableRow returns the index and content of the first data row in the
+// table in model.View() for which match returns true. Fails the test
+// immediately if no row matches.
+//
+// Example:
+//
+//	_, row := tuitestkit.FindTableRow(t, m, func(r tuitestkit.TableRow) bool {
+//	    return r["ID"] == "TASK-3"
+//	})
+//	if row["Status"] != "Done" { ... }
+func FindTableRow(t testing.TB, model tea.Model, match func(TableRow) bool) (int, TableRow) {
+	t.Helper()
+	tb := mustViewTable(t, model, "FindTableRow")
+	for i := range tb.Rows {
+		if r := tb.Row(i); match(r) {
+			return i, r
+		}
+	}
+	fatalView(t, model.View(), "FindTableRow: no row matches (table has %d rows)\n%s", len(tb.Rows), formatTable(tb))
+	return -1, nil
+}
+
+// mustViewTable parses the table in model.View() or fails the test.
+func mustViewTable(t testing.TB, model tea.Model, caller string) Table {
+	t.Helper()
+	tb, err := ViewTable(model)
+	if err != nil {
+		fatalView(t, model.View(), "%s: %v", caller, err)
+	}
+	return tb
+}
+
+// formatTable renders parsed cells for failure messages.
+func formatTable(tb Table) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  header %q\n", tb.Header)
+	for i, row := range tb.Rows {
+		fmt.Fprintf(&b, "  [%d] %q\n", i, row)
+	}
+	return b.String()
+}
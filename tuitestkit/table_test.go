@@ -0,0 +1,170 @@
+package tuitestkit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// borderlessTableView mimics bubbles/table output: padded header, a rule
+// line under it, a right-aligned Est column and a multi-word status.
+const borderlessTableView = "" +
+	" ID       Title             Status         Est \n" +
+	"───────────────────────────────────────────────\n" +
+	" TASK-1   Write parser      Done            3h \n" +
+	" TASK-2   Review PR         In progress    12h \n" +
+	" TASK-3   Fix flaky test    Todo            1h \n" +
+	"\n" +
+	"↑/↓ navigate • q quit"
+
+func newLipglossTable() string {
+	return table.New().
+		Headers("ID", "Title", "Status").
+		Row("TASK-1", "Write parser", "Done").
+		Row("TASK-2", "Review PR", "In progress").
+		String()
+}
+
+// --- ParseTable tests ---
+
+func TestParseTable_Bordered(t *testing.T) {
+	tb, err := ParseTable(newLipglossTable())
+	if err != nil {
+		t.Fatalf("ParseTable: %v", err)
+	}
+	if !reflect.DeepEqual(tb.Header, []string{"ID", "Title", "Status"}) {
+		t.Errorf("Header = %q", tb.Header)
+	}
+	want := [][]string{
+		{"TASK-1", "Write parser", "Done"},
+		{"TASK-2", "Review PR", "In progress"},
+	}
+	if !reflect.DeepEqual(tb.Rows, want) {
+		t.Errorf("Rows = %q, want %q", tb.Rows, want)
+	}
+}
+
+func TestParseTable_Borderless(t *testing.T) {
+	tb, err := ParseTable(borderlessTableView)
+	if err != nil {
+		t.Fatalf("ParseTable: %v", err)
+	}
+	if !reflect.DeepEqual(tb.Header, []string{"ID", "Title", "Status", "Est"}) {
+		t.Errorf("Header = %q", tb.Header)
+	}
+	if len(tb.Rows) != 3 {
+		t.Fatalf("got %d rows, want 3 (help line must not be parsed)", len(tb.Rows))
+	}
+	if got := tb.Rows[1]; !reflect.DeepEqual(got, []string{"TASK-2", "Review PR", "In progress", "12h"}) {
+		t.Errorf("Rows[1] = %q", got)
+	}
+}
+
+func TestParseTable_BorderlessMultiWordHeader(t *testing.T) {
+	tb, err := ParseTable("ID      Last Updated  Status\n1       now           ok\n2       1h            failed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ID", "Last Updated", "Status"}; !reflect.DeepEqual(tb.Header, want) {
+		t.Errorf("Header = %q, want %q", tb.Header, want)
+	}
+	if want := []string{"2", "1h", "failed"}; len(tb.Rows) != 2 || !reflect.DeepEqual(tb.Rows[1], want) {
+		t.Errorf("Rows = %q, want second row %q", tb.Rows, want)
+	}
+
+	// Single-space header: the blank gutters are all there is to go on.
+	tb, err = ParseTable("ID Name\n1  alice\n22 bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"22", "bob"}; len(tb.Rows) != 2 || !reflect.DeepEqual(tb.Rows[1], want) {
+		t.Errorf("Rows = %q, want second row %q", tb.Rows, want)
+	}
+}
+
+func TestParseTable_Cells(t *testing.T) {
+	tb, err := ParseTable(borderlessTableView)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cells := tb.Cells()
+	if len(cells) != 4 || cells[0][0] != "ID" || cells[3][0] != "TASK-3" {
+		t.Errorf("Cells = %q", cells)
+	}
+}
+
+func TestParseTable_SharedBorderPanes(t *testing.T) {
+	for name, view := range map[string]string{
+		"titled":   sharedBorderView,
+		"untitled": "┌──────┬──────┬──────┐\n│ a    │ b    │ c    │\n│ d    │ e    │ f    │\n├──────┴──────┴──────┤\n│ status             │\n└────────────────────┘",
+		"no rule":  "┌────┬────┬────┐\n│ a  │ b  │ c  │\n│ d  │ e  │ f  │\n└────┴────┴────┘",
+	} {
+		if tb, ok := newCellGrid(view).borderedTable(); ok {
+			t.Errorf("%s: panes sharing borders parsed as a bordered table: %+v", name, tb)
+		}
+	}
+}
+
+func TestParseTable_Empty(t *testing.T) {
+	if _, err := ParseTable("\n\n"); err == nil {
+		t.Error("ParseTable should fail on a blank view")
+	}
+}
+
+func TestTable_CellAndRow(t *testing.T) {
+	tb, err := ParseTable(borderlessTableView)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := tb.Cell(2, "Title"); !ok || got != "Fix flaky test" {
+		t.Errorf("Cell(2, Title) = %q, %v", got, ok)
+	}
+	if _, ok := tb.Cell(0, "Owner"); ok {
+		t.Error("Cell should report a missing column")
+	}
+	if _, ok := tb.Cell(9, "ID"); ok {
+		t.Error("Cell should report a missing row")
+	}
+	if row := tb.Row(0); row["Status"] != "Done" || row["Est"] != "3h" {
+		t.Errorf("Row(0) = %v", row)
+	}
+}
+
+// --- Assertion tests ---
+
+func TestTableCell(t *testing.T) {
+	m := styledModel{content: newLipglossTable()}
+	if got := TableCell(t, m, 1, "Status"); got != "In progress" {
+		t.Errorf("TableCell = %q, want %q", got, "In progress")
+	}
+	fake := runFatal(func(t *testing.T) { TableCell(t, m, 0, "Owner") })
+	if !fake.Failed() {
+		t.Error("TableCell should fail on a missing column")
+	}
+}
+
+func TestAssertTableCell(t *testing.T) {
+	m := styledModel{content: borderlessTableView}
+	AssertTableCell(t, m, 0, "Status", "Done")
+
+	fake := &testing.T{}
+	AssertTableCell(fake, m, 0, "Status", "Todo")
+	if !fake.Failed() {
+		t.Error("AssertTableCell should fail on a different value")
+	}
+}
+
+func TestFindTableRow(t *testing.T) {
+	m := styledModel{content: borderlessTableView}
+	idx, row := FindTableRow(t, m, func(r TableRow) bool { return r["ID"] == "TASK-3" })
+	if idx != 2 || row["Status"] != "Todo" {
+		t.Errorf("FindTableRow = %d, %v", idx, row)
+	}
+	fake := runFatal(func(t *testing.T) {
+		FindTableRow(t, m, func(r TableRow) bool { return r["ID"] == "TASK-9" })
+	})
+	if !fake.Failed() {
+		t.Error("FindTableRow should fail when no row matches")
+	}
+}
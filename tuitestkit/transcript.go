@@ -0,0 +1,209 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Transcript records the causal chain of a command flow — message → Update
+// → Cmd → mock calls → message → ... — as text for a golden file, so a
+// change such as "now calls List twice" shows up as a transcript diff
+// without an AssertCalledN for every method:
+//
+//	mock := NewExecutorMock()
+//	tr := tuitestkit.NewTranscript(newModel(mock), &mock.MockCallRecorder)
+//	tr.Send(tuitestkit.Key("enter")).Run()
+//	tr.Snapshot(t, "load-boards")
+//
+//	// testdata/snapshots/load-boards.golden
+//	send tea.KeyMsg "enter"
+//	  → cmd 1
+//	cmd 1
+//	  call List("boards")
+//	  msg main.boardsLoadedMsg
+//	    .boards[0].name = "Todo"
+//	  → cmd 2
+//	cmd 2
+//	  batch → cmd 3, cmd 4
+//
+// Cmds are numbered in the order they are returned and run first in, first
+// out. Mock calls are listed under the Update or Cmd that made them. Like
+// Send, Init() is not called and Update must return the model's own type.
+type Transcript[M tea.Model] struct {
+	model     M
+	recorders []*MockCallRecorder
+	seen      []int
+	queue     []transcriptCmd
+	cmds      int
+	lines     []string
+	quit      bool
+}
+
+// transcriptCmd is a Cmd waiting to run, with its number.
+type transcriptCmd struct {
+	id  int
+	cmd tea.Cmd
+}
+
+// maxTranscriptCmds bounds Run, so a Cmd that always schedules another one
+// (a ticker) cannot loop forever.
+const maxTranscriptCmds = 1000
+
+// NewTranscript starts a transcript of model. Calls on the recorders are
+// listed as they happen; calls recorded before NewTranscript are ignored.
+func NewTranscript[M tea.Model](model M, recorders ...*MockCallRecorder) *Transcript[M] {
+	tr := &Transcript[M]{model: model, recorders: recorders, seen: make([]int, len(recorders))}
+	for i, r := range recorders {
+		tr.seen[i] = len(r.Calls())
+	}
+	return tr
+}
+
+// Send sends msgs to the model, recording each message and the Cmd (if any)
+// returned by Update. Cmds are queued for Run.
+func (tr *Transcript[M]) Send(msgs ...tea.Msg) *Transcript[M] {
+	for _, msg := range msgs {
+		tr.add("send " + describeMsg(msg, "  "))
+		tr.update(msg)
+	}
+	return tr
+}
+
+// Run runs the queued Cmds, and the Cmds their messages lead to, until none
+// are left or a Cmd returns tea.Quit. Each message a Cmd returns is sent to
+// the model; batches (tea.Batch, tea.Sequence) queue their Cmds.
+func (tr *Transcript[M]) Run() *Transcript[M] {
+	for ran := 0; len(tr.queue) > 0 && !tr.quit; ran++ {
+		if ran == maxTranscriptCmds {
+			tr.add(fmt.Sprintf("stopped after %d cmds, %d still queued", ran, len(tr.queue)))
+			return tr
+		}
+		next := tr.queue[0]
+		tr.queue = tr.queue[1:]
+		tr.add(fmt.Sprintf("cmd %d", next.id))
+		msg := next.cmd()
+		tr.addCalls()
+		if cmds, ok := batchCmds(msg); ok {
+			var ids []string
+			for _, c := range cmds {
+				if c != nil {
+					ids = append(ids, fmt.Sprintf("cmd %d", tr.enqueue(c)))
+				}
+			}
+			tr.add("  batch → " + listOrNone(ids))
+			continue
+		}
+		tr.add("  msg " + describeMsg(msg, "    "))
+		switch msg.(type) {
+		case nil:
+		case tea.QuitMsg:
+			tr.quit = true
+		default:
+			tr.update(msg)
+		}
+	}
+	return tr
+}
+
+// Model returns the model after the last message.
+func (tr *Transcript[M]) Model() M {
+	return tr.model
+}
+
+// String returns the transcript text.
+func (tr *Transcript[M]) String() string {
+	return strings.Join(tr.lines, "\n")
+}
+
+// Snapshot compares (or updates) the transcript against the golden file
+// named `name`.
+func (tr *Transcript[M]) Snapshot(t *testing.T, name string, opts ...SnapshotOption) {
+	t.Helper()
+	snapshot(t, tr.String(), name, 3, opts...)
+}
+
+// update sends msg to the model and records the calls it made and its Cmd.
+func (tr *Transcript[M]) update(msg tea.Msg) {
+	updated, cmd := tr.model.Update(msg)
+	concrete, ok := updated.(M)
+	if !ok {
+		panic(fmt.Sprintf(
+			"tuitestkit.Transcript: Update returned %T, expected %T — broken Update implementation",
+			updated, tr.model,
+		))
+	}
+	tr.model = concrete
+	tr.addCalls()
+	if cmd != nil {
+		tr.add(fmt.Sprintf("  → cmd %d", tr.enqueue(cmd)))
+	}
+}
+
+// enqueue queues cmd for Run and returns its number.
+func (tr *Transcript[M]) enqueue(cmd tea.Cmd) int {
+	tr.cmds++
+	tr.queue = append(tr.queue, transcriptCmd{id: tr.cmds, cmd: cmd})
+	return tr.cmds
+}
+
+// addCalls records the mock calls made since the last check.
+func (tr *Transcript[M]) addCalls() {
+	for i, r := range tr.recorders {
+		calls := r.Calls()
+		for _, c := range calls[min(tr.seen[i], len(calls)):] {
+			args := make([]string, len(c.Args))
+			for j, a := range c.Args {
+				args[j] = inlineState(reflect.ValueOf(a))
+			}
+			tr.add(fmt.Sprintf("  call %s(%s)", c.Method, strings.Join(args, ", ")))
+		}
+		tr.seen[i] = len(calls)
+	}
+}
+
+// add appends lines to the transcript.
+func (tr *Transcript[M]) add(text string) {
+	tr.lines = append(tr.lines, strings.Split(text, "\n")...)
+}
+
+var cmdType = reflect.TypeOf(tea.Cmd(nil))
+
+// batchCmds returns the Cmds of a batch-like message: tea.BatchMsg, or the
+// unexported message of tea.Sequence (any slice of tea.Cmd).
+func batchCmds(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	if !v.IsValid() || v.Kind() != reflect.Slice || v.Type().Elem() != cmdType {
+		return nil, false
+	}
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i] = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds, true
+}
+
+// describeMsg names a message by type, followed by its value on the same
+// line when it fits ("tea.KeyMsg "enter"", "main.tickMsg {}"), or its
+// FormatState lines indented by indent.
+func describeMsg(msg tea.Msg, indent string) string {
+	if msg == nil {
+		return "nil"
+	}
+	typ := fmt.Sprintf("%T", msg)
+	switch m := msg.(type) {
+	case tea.KeyMsg:
+		return typ + " " + strconv.Quote(m.String())
+	case tea.MouseMsg:
+		return typ + " " + strconv.Quote(m.String())
+	}
+	lines := strings.Split(FormatState(msg), "\n")
+	if len(lines) == 1 && strings.HasPrefix(lines[0], ". = ") {
+		return typ + " " + strings.TrimPrefix(lines[0], ". = ")
+	}
+	return typ + "\n" + indent + strings.Join(lines, "\n"+indent)
+}
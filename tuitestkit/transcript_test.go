@@ -0,0 +1,139 @@
+package tuitestkit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Test model: loads boards through a mock ---
+
+type boardsMock struct {
+	MockCallRecorder
+}
+
+func (m *boardsMock) List(kind string) []string {
+	m.Record("List", kind)
+	return []string{"Todo", "Done"}
+}
+
+type boardsLoadedMsg struct {
+	boards []string
+}
+
+type boardsFailedMsg struct {
+	err error
+}
+
+type loaderModel struct {
+	mock   *boardsMock
+	boards []string
+}
+
+func (m loaderModel) Init() tea.Cmd { return nil }
+
+func (m loaderModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			return m, func() tea.Msg { return boardsLoadedMsg{boards: m.mock.List("boards")} }
+		case "q":
+			return m, tea.Quit
+		}
+	case boardsLoadedMsg:
+		m.boards = msg.boards
+		return m, tea.Batch(
+			func() tea.Msg { return incMsg{} },
+			func() tea.Msg { return nil },
+		)
+	}
+	return m, nil
+}
+
+func (m loaderModel) View() string { return strings.Join(m.boards, "\n") }
+
+// --- Transcript ---
+
+func TestTranscript_CausalChain(t *testing.T) {
+	mock := &boardsMock{}
+	mock.Record("Setup") // before the transcript: not listed
+	tr := NewTranscript(loaderModel{mock: mock}, &mock.MockCallRecorder)
+	tr.Send(Key("enter")).Run()
+
+	want := strings.Join([]string{
+		`send tea.KeyMsg "enter"`,
+		`  → cmd 1`,
+		`cmd 1`,
+		`  call List("boards")`,
+		`  msg tuitestkit.boardsLoadedMsg`,
+		`    .boards[0] = "Todo"`,
+		`    .boards[1] = "Done"`,
+		`  → cmd 2`,
+		`cmd 2`,
+		`  batch → cmd 3, cmd 4`,
+		`cmd 3`,
+		`  msg tuitestkit.incMsg {}`,
+		`cmd 4`,
+		`  msg nil`,
+	}, "\n")
+	if got := tr.String(); got != want {
+		t.Errorf("transcript =\n%s\nwant\n%s", got, want)
+	}
+	if len(tr.Model().boards) != 2 {
+		t.Errorf("model boards = %v, want loaded", tr.Model().boards)
+	}
+}
+
+func TestTranscript_StopsAtQuit(t *testing.T) {
+	tr := NewTranscript(loaderModel{mock: &boardsMock{}})
+	tr.Send(Key("q"), Key("enter")).Run()
+
+	got := tr.String()
+	if !strings.Contains(got, "cmd 1\n  msg tea.QuitMsg {}") {
+		t.Errorf("quit should be recorded:\n%s", got)
+	}
+	if strings.Contains(got, "\ncmd 2") {
+		t.Errorf("no cmd should run after quit:\n%s", got)
+	}
+}
+
+func TestTranscript_RunawayCmds(t *testing.T) {
+	var loop tea.Cmd
+	loop = func() tea.Msg { return cmdMsg{cmd: loop} }
+	tr := NewTranscript(counterModel{})
+	tr.Send(cmdMsg{cmd: loop}).Run()
+
+	if !strings.HasSuffix(tr.String(), "stopped after 1000 cmds, 1 still queued") {
+		t.Errorf("runaway cmds should stop with a note, got tail:\n%s", tr.String()[len(tr.String())-80:])
+	}
+}
+
+func TestTranscript_CallArgs(t *testing.T) {
+	rec := &MockCallRecorder{}
+	tr := NewTranscript(counterModel{}, rec)
+	rec.Record("Execute", "ls", []string{"-la"}, errors.New("boom"), nil)
+	tr.addCalls()
+
+	if got := tr.String(); got != `  call Execute("ls", {[0] = "-la"}, error("boom"), nil)` {
+		t.Errorf("call line = %s", got)
+	}
+}
+
+func TestTranscript_Snapshot(t *testing.T) {
+	dir := t.TempDir()
+	withSnapshotDir(t, dir)
+	CreateMissingSnapshots = true
+
+	mock := &boardsMock{}
+	NewTranscript(loaderModel{mock: mock}, &mock.MockCallRecorder).Send(Key("enter")).Run().Snapshot(t, "load")
+
+	data, err := os.ReadFile(filepath.Join(dir, "load.golden"))
+	if err != nil || !strings.Contains(string(data), `call List("boards")`) {
+		t.Errorf("golden transcript = %q, %v", data, err)
+	}
+}
@@ -45,11 +45,11 @@ func ViewLineContains(t testing.TB, model tea.Model, lineIdx int, text string) {
 	t.Helper()
 	lines := ViewLines(model)
 	if lineIdx < 0 || lineIdx >= len(lines) {
-		t.Errorf("ViewLineContains: line index %d out of range (view has %d lines)", lineIdx, len(lines))
+		failView(t, model.View(), "ViewLineContains: line index %d out of range (view has %d lines)", lineIdx, len(lines))
 		return
 	}
 	if !strings.Contains(lines[lineIdx], text) {
-		t.Errorf("ViewLineContains: line %d = %q, want it to contain %q", lineIdx, lines[lineIdx], text)
+		failView(t, model.View(), "ViewLineContains: line %d = %q, want it to contain %q", lineIdx, lines[lineIdx], text)
 	}
 }
 
@@ -59,14 +59,21 @@ func ViewLineEquals(t testing.TB, model tea.Model, lineIdx int, text string) {
 	t.Helper()
 	lines := ViewLines(model)
 	if lineIdx < 0 || lineIdx >= len(lines) {
-		t.Errorf("ViewLineEquals: line index %d out of range (view has %d lines)", lineIdx, len(lines))
+		failView(t, model.View(), "ViewLineEquals: line index %d out of range (view has %d lines)", lineIdx, len(lines))
 		return
 	}
 	if lines[lineIdx] != text {
-		t.Errorf("ViewLineEquals: line %d = %q, want %q", lineIdx, lines[lineIdx], text)
+		failView(t, model.View(), "ViewLineEquals: line %d = %q, want %q\n%s", lineIdx, lines[lineIdx], text, lineDiff(text, lines[lineIdx]))
 	}
 }
 
+// ViewEquals asserts that model.View() equals expected exactly after ANSI
+// stripping. On mismatch it prints the same diff as the snapshot functions.
+func ViewEquals(t testing.TB, model tea.Model, expected string) {
+	t.Helper()
+	EqualsStr(t, model.View(), expected)
+}
+
 // ViewMatchesRegex asserts that model.View() (after ANSI stripping) matches
 // the given regular expression pattern. Fails if the pattern is invalid or
 // does not match.
@@ -83,7 +90,18 @@ func ContainsStr(t testing.TB, view string, text string) {
 	t.Helper()
 	stripped := StripANSI(view)
 	if !strings.Contains(stripped, text) {
-		t.Errorf("ContainsStr: view does not contain %q\n  stripped view: %q", text, stripped)
+		failView(t, view, "ContainsStr: view does not contain %q\n  stripped view: %q", text, stripped)
+	}
+}
+
+// EqualsStr asserts that the given view string equals expected exactly after
+// stripping ANSI escape codes. On mismatch it prints a unified diff with
+// changed characters marked.
+func EqualsStr(t testing.TB, view string, expected string) {
+	t.Helper()
+	stripped := StripANSI(view)
+	if stripped != expected {
+		failView(t, view, "EqualsStr: view differs from expected:\n%s", unifiedDiff(expected, stripped))
 	}
 }
 
@@ -93,7 +111,7 @@ func NotContainsStr(t testing.TB, view string, text string) {
 	t.Helper()
 	stripped := StripANSI(view)
 	if strings.Contains(stripped, text) {
-		t.Errorf("NotContainsStr: view unexpectedly contains %q\n  stripped view: %q", text, stripped)
+		failView(t, view, "NotContainsStr: view unexpectedly contains %q\n  stripped view: %q", text, stripped)
 	}
 }
 
@@ -124,6 +142,6 @@ func MatchesRegexStr(t testing.TB, view string, pattern string) {
 	}
 	stripped := StripANSI(view)
 	if !re.MatchString(stripped) {
-		t.Errorf("MatchesRegexStr: view does not match pattern %q\n  stripped view: %q", pattern, stripped)
+		failView(t, view, "MatchesRegexStr: view does not match pattern %q\n  stripped view: %q", pattern, stripped)
 	}
 }
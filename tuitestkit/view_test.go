@@ -1,6 +1,7 @@
 package tuitestkit
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -303,3 +304,35 @@ func TestViewMatchesRegex_Fail(t *testing.T) {
 		t.Error("ViewMatchesRegex should have failed")
 	}
 }
+
+func TestViewLineEquals_Fail_MarksDifference(t *testing.T) {
+	tb := &mockTB{}
+	m := styledModel{content: "Tasks (12)\nsecond"}
+	ViewLineEquals(tb, m, 0, "Tasks (3)")
+	if !tb.failed || !strings.Contains(tb.logs[0], "   got  Tasks (12)\n               ^^\n") {
+		t.Errorf("failure should mark the differing characters: %v", tb.logs)
+	}
+}
+
+func TestEqualsStr(t *testing.T) {
+	EqualsStr(t, ansiWrap("one")+"\ntwo", "one\ntwo")
+
+	tb := &mockTB{}
+	EqualsStr(tb, "one\ntwo ", "one\ntwo")
+	if !tb.failed {
+		t.Fatal("EqualsStr should fail on trailing whitespace")
+	}
+	if !strings.Contains(tb.logs[0], "+   2  two·\n") {
+		t.Errorf("diff should show the trailing space: %s", tb.logs[0])
+	}
+}
+
+func TestViewEquals(t *testing.T) {
+	ViewEquals(t, styledModel{content: ansiWrap("exact")}, "exact")
+
+	tb := &mockTB{}
+	ViewEquals(tb, styledModel{content: "actual"}, "expected")
+	if !tb.failed || !strings.Contains(tb.logs[0], "--- expected\n+++ actual\n") {
+		t.Errorf("ViewEquals should fail with a diff: %v", tb.logs)
+	}
+}
@@ -0,0 +1,245 @@
+package tuitestkit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WrapMatch describes where wrapped text was found in a view.
+type WrapMatch struct {
+	// Pane is the heading of the innermost pane holding the match (see
+	// FindPane), or "" when the match was found in the view as a whole.
+	Pane string
+	// Rows lists the view rows the matched text spans, top to bottom.
+	// A single entry means the text was not wrapped.
+	Rows []int
+}
+
+// Wrapped reports whether the match spans more than one line.
+func (w WrapMatch) Wrapped() bool {
+	return len(w.Rows) > 1
+}
+
+// String describes the match location, e.g. `pane "Details", rows 3-4`.
+func (w WrapMatch) String() string {
+	where := "view"
+	if w.Pane != "" {
+		where = fmt.Sprintf("pane %q", w.Pane)
+	}
+	if len(w.Rows) == 1 {
+		return fmt.Sprintf("%s, row %d", where, w.Rows[0])
+	}
+	return fmt.Sprintf("%s, rows %d-%d", where, w.Rows[0], w.Rows[len(w.Rows)-1])
+}
+
+// wrapItem is one rune of normalised text, tagged with its view row.
+// Soft items mark the join between two wrapped lines; hard items mark a
+// paragraph break (blank line) that wrapped text never crosses.
+type wrapItem struct {
+	r    rune
+	row  int
+	soft bool
+	hard bool
+}
+
+// wrapRegion is a block of lines that may contain soft-wrapped text.
+type wrapRegion struct {
+	pane  string
+	rows  []int
+	lines []string
+}
+
+// FindWrapped searches model.View() for text, tolerating soft wraps. See
+// FindWrappedStr.
+func FindWrapped(model tea.Model, text string) (WrapMatch, bool) {
+	return FindWrappedStr(model.View(), text)
+}
+
+// FindWrappedStr searches view for text, tolerating the way lipgloss wraps
+// long content. Whitespace in both text and view is normalised to single
+// spaces, and consecutive lines are joined so that a wrap point may fall
+// either on a space or inside a word (hard-wrapped long words, hyphens).
+//
+// Each detected pane is searched on its own first, innermost first, so text
+// wrapped inside one of several side-by-side panes is found and border runes
+// never sit between the wrapped parts. The whole view, with border runes
+// blanked out, is searched last.
+func FindWrappedStr(view string, text string) (WrapMatch, bool) {
+	needle := []rune(strings.Join(strings.Fields(text), " "))
+	if len(needle) == 0 {
+		return WrapMatch{}, false
+	}
+	for _, region := range wrapRegions(view) {
+		if m, ok := matchWrapped(region, needle); ok {
+			return m, true
+		}
+	}
+	return WrapMatch{}, false
+}
+
+// findWrappedIn searches only the cells of view inside r.
+func findWrappedIn(view string, text string, r Region) (WrapMatch, bool) {
+	needle := []rune(strings.Join(strings.Fields(text), " "))
+	if len(needle) == 0 {
+		return WrapMatch{}, false
+	}
+	g := newCellGrid(view)
+	region := wrapRegion{}
+	for row := r.Row; row < r.Row+r.Height && row < g.height(); row++ {
+		region.rows = append(region.rows, row)
+		region.lines = append(region.lines, g.text(row, r.Col, r.Col+r.Width))
+	}
+	return matchWrapped(region, needle)
+}
+
+// wrapRegions returns the regions to search: panes from smallest to largest,
+// then the whole view.
+func wrapRegions(view string) []wrapRegion {
+	g := newCellGrid(view)
+	boxes := BoxesStr(view)
+	sort.SliceStable(boxes, func(i, j int) bool {
+		return boxes[i].Bounds.Width*boxes[i].Bounds.Height < boxes[j].Bounds.Width*boxes[j].Bounds.Height
+	})
+
+	var regions []wrapRegion
+	for _, b := range boxes {
+		region := wrapRegion{pane: b.heading()}
+		inner := b.Inner()
+		for i, line := range b.Lines {
+			region.rows = append(region.rows, inner.Row+i)
+			region.lines = append(region.lines, blankBoxRunes(line))
+		}
+		regions = append(regions, region)
+	}
+
+	whole := wrapRegion{}
+	for row := 0; row < g.height(); row++ {
+		whole.rows = append(whole.rows, row)
+		whole.lines = append(whole.lines, blankBoxRunes(g.text(row, 0, len(g.rows[row]))))
+	}
+	return append(regions, whole)
+}
+
+// blankBoxRunes replaces box-drawing runes with spaces.
+func blankBoxRunes(s string) string {
+	return strings.Map(func(r rune) rune {
+		if _, ok := boxRunes[r]; ok {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// matchWrapped looks for needle in the normalised text of region.
+func matchWrapped(region wrapRegion, needle []rune) (WrapMatch, bool) {
+	var hay []wrapItem
+	for i, line := range region.lines {
+		row := region.rows[i]
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			hay = append(hay, wrapItem{hard: true, row: row})
+			continue
+		}
+		if len(hay) > 0 && !hay[len(hay)-1].hard {
+			hay = append(hay, wrapItem{soft: true, row: row})
+		}
+		for j, w := range words {
+			if j > 0 {
+				hay = append(hay, wrapItem{r: ' ', row: row})
+			}
+			for _, r := range w {
+				hay = append(hay, wrapItem{r: r, row: row})
+			}
+		}
+	}
+
+	for start := range hay {
+		if hay[start].soft || hay[start].hard {
+			continue
+		}
+		if end, ok := matchWrappedAt(hay, start, needle); ok {
+			m := WrapMatch{Pane: region.pane}
+			for k := start; k <= end; k++ {
+				if n := len(m.Rows); n == 0 || m.Rows[n-1] != hay[k].row {
+					m.Rows = append(m.Rows, hay[k].row)
+				}
+			}
+			return m, true
+		}
+	}
+	return WrapMatch{}, false
+}
+
+// matchWrappedAt matches needle against hay starting at start and returns
+// the index of the last matched item. A soft join matches a space in the
+// needle, or nothing at all when the wrap split a word.
+func matchWrappedAt(hay []wrapItem, start int, needle []rune) (int, bool) {
+	k := start
+	for j := 0; j < len(needle); {
+		if k >= len(hay) || hay[k].hard {
+			return 0, false
+		}
+		switch {
+		case hay[k].soft && needle[j] == ' ':
+			j++
+		case hay[k].soft:
+		case hay[k].r == needle[j]:
+			j++
+		default:
+			return 0, false
+		}
+		k++
+	}
+	return k - 1, true
+}
+
+// --- Assertions ---
+
+// ViewContainsWrapped asserts that model.View() contains text, even if
+// lipgloss wrapped it across several lines or a pane border sits between the
+// wrapped parts. Returns where the text was found. See FindWrappedStr.
+func ViewContainsWrapped(t testing.TB, model tea.Model, text string) WrapMatch {
+	t.Helper()
+	return ContainsWrappedStr(t, model.View(), text)
+}
+
+// ViewNotContainsWrapped asserts that model.View() does NOT contain text,
+// wrapped or not.
+func ViewNotContainsWrapped(t testing.TB, model tea.Model, text string) {
+	t.Helper()
+	NotContainsWrappedStr(t, model.View(), text)
+}
+
+// ViewContainsWrappedIn is like ViewContainsWrapped but only searches the
+// cells inside r, for text wrapped within an unbordered column.
+func ViewContainsWrappedIn(t testing.TB, model tea.Model, text string, r Region) WrapMatch {
+	t.Helper()
+	view := model.View()
+	m, ok := findWrappedIn(view, text, r)
+	if !ok {
+		failView(t, view, "ViewContainsWrappedIn: region %s does not contain %q (wrapped or not)\n  stripped view: %q", r, text, StripANSI(view))
+	}
+	return m
+}
+
+// ContainsWrappedStr is the string-based variant of ViewContainsWrapped.
+func ContainsWrappedStr(t testing.TB, view string, text string) WrapMatch {
+	t.Helper()
+	m, ok := FindWrappedStr(view, text)
+	if !ok {
+		failView(t, view, "ContainsWrappedStr: view does not contain %q (wrapped or not)\n  stripped view: %q", text, StripANSI(view))
+	}
+	return m
+}
+
+// NotContainsWrappedStr is the string-based variant of ViewNotContainsWrapped.
+func NotContainsWrappedStr(t testing.TB, view string, text string) {
+	t.Helper()
+	if m, ok := FindWrappedStr(view, text); ok {
+		failView(t, view, "NotContainsWrappedStr: view unexpectedly contains %q at %s", text, m)
+	}
+}
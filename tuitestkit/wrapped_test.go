@@ -0,0 +1,119 @@
+package tuitestkit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const wrappedMessage = "failed to load tasks: connection refused by remote host"
+
+// --- FindWrappedStr tests ---
+
+func TestFindWrappedStr_SingleLine(t *testing.T) {
+	m, ok := FindWrappedStr("header\nstatus: ready\n", "status: ready")
+	if !ok {
+		t.Fatal("FindWrappedStr should find unwrapped text")
+	}
+	if m.Wrapped() || !reflect.DeepEqual(m.Rows, []int{1}) {
+		t.Errorf("match = %+v, want row 1 only", m)
+	}
+}
+
+func TestFindWrappedStr_LipglossWordWrap(t *testing.T) {
+	view := lipgloss.NewStyle().Width(20).Render(wrappedMessage)
+	ContainsStr(t, view, "failed to load") // sanity: first line intact
+	m, ok := FindWrappedStr(view, wrappedMessage)
+	if !ok {
+		t.Fatalf("FindWrappedStr should join wrapped lines:\n%s", view)
+	}
+	if !m.Wrapped() || m.Rows[0] != 0 {
+		t.Errorf("match = %+v, want rows starting at 0", m)
+	}
+}
+
+func TestFindWrappedStr_WordSplitAcrossLines(t *testing.T) {
+	view := "identifier: abcdefghij\nklmnop done"
+	if _, ok := FindWrappedStr(view, "abcdefghijklmnop done"); !ok {
+		t.Error("FindWrappedStr should match a word hard-wrapped mid-way")
+	}
+}
+
+func TestFindWrappedStr_InsidePane(t *testing.T) {
+	style := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Width(18)
+	left := style.Render("Details\n" + wrappedMessage)
+	right := style.Render("Logs\nunrelated words here")
+	view := lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+
+	m, ok := FindWrappedStr(view, wrappedMessage)
+	if !ok {
+		t.Fatalf("FindWrappedStr should find text wrapped inside a pane:\n%s", view)
+	}
+	if m.Pane != "Details" {
+		t.Errorf("Pane = %q, want %q", m.Pane, "Details")
+	}
+	if !m.Wrapped() || m.Rows[0] != 2 {
+		t.Errorf("Rows = %v, want wrapped rows starting at 2", m.Rows)
+	}
+}
+
+func TestFindWrappedStr_DoesNotCrossBlankLines(t *testing.T) {
+	if _, ok := FindWrappedStr("first part\n\nsecond part", "part second"); ok {
+		t.Error("FindWrappedStr should not join across a blank line")
+	}
+}
+
+func TestFindWrappedStr_NormalisesWhitespace(t *testing.T) {
+	if _, ok := FindWrappedStr("a   b\tc", "a b  c"); !ok {
+		t.Error("FindWrappedStr should collapse whitespace on both sides")
+	}
+}
+
+func TestWrapMatch_String(t *testing.T) {
+	got := WrapMatch{Pane: "Details", Rows: []int{3, 4}}.String()
+	if got != `pane "Details", rows 3-4` {
+		t.Errorf("String = %q", got)
+	}
+}
+
+// --- Assertion tests ---
+
+func TestViewContainsWrapped(t *testing.T) {
+	m := styledModel{content: lipgloss.NewStyle().Width(16).Render(wrappedMessage)}
+	ViewContainsWrapped(t, m, wrappedMessage)
+
+	fake := &testing.T{}
+	ViewContainsWrapped(fake, m, "connection accepted")
+	if !fake.Failed() {
+		t.Error("ViewContainsWrapped should fail for missing text")
+	}
+}
+
+func TestViewNotContainsWrapped(t *testing.T) {
+	m := styledModel{content: "connection\nrefused"}
+	ViewNotContainsWrapped(t, m, "connection accepted")
+
+	fake := &testing.T{}
+	ViewNotContainsWrapped(fake, m, "connection refused")
+	if !fake.Failed() {
+		t.Error("ViewNotContainsWrapped should fail for wrapped text")
+	}
+}
+
+func TestViewContainsWrappedIn(t *testing.T) {
+	// Two unbordered columns: the message wraps in the left one.
+	view := "failed to    | other\n" +
+		"load tasks   | column"
+	m := styledModel{content: view}
+	match := ViewContainsWrappedIn(t, m, "failed to load tasks", Region{Row: 0, Col: 0, Width: 12, Height: 2})
+	if !reflect.DeepEqual(match.Rows, []int{0, 1}) {
+		t.Errorf("Rows = %v, want [0 1]", match.Rows)
+	}
+
+	fake := &testing.T{}
+	ViewContainsWrappedIn(fake, m, "other column", Region{Row: 0, Col: 0, Width: 12, Height: 2})
+	if !fake.Failed() {
+		t.Error("ViewContainsWrappedIn should ignore text outside the region")
+	}
+}